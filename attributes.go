@@ -2,17 +2,21 @@ package goform
 
 import (
 	"fmt"
-	"math/rand"
+	"math/rand/v2"
+	"reflect"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	AriaHintTemplate   = "%s-hint"
-	AriaHintAttribute  = "aria-describedby"
-	AriaErrorTemplate  = "%s-error"
-	AriaErrorAttribute = "aria-errormessage"
+	AriaHintTemplate         = "%s-hint"
+	AriaHintAttribute        = "aria-describedby"
+	AriaErrorTemplate        = "%s-error"
+	AriaErrorAttribute       = "aria-errormessage"
+	AriaDescribedByAttribute = "aria-describedby"
 )
 
 var attributes = []string{
@@ -32,9 +36,17 @@ var attributes = []string{
 	"disabled",
 	"draggable",
 	"enctype",
+	"enterkeyhint",
+	"form",
+	"formaction",
+	"formenctype",
+	"formmethod",
+	"formnovalidate",
+	"formtarget",
 	"hidden",
 	"href",
 	"id",
+	"inputmode",
 	"label",
 	"lang",
 	"list",
@@ -45,9 +57,13 @@ var attributes = []string{
 	"minlength",
 	"multiple",
 	"name",
+	"nonce",
 	"novalidate",
 	"pattern",
 	"placeholder",
+	"popover",
+	"popovertarget",
+	"popovertargetaction",
 	"readonly",
 	"required",
 	"role",
@@ -64,6 +80,104 @@ var attributes = []string{
 	"value",
 }
 
+var (
+	htmxPrefixes   = []string{"hx-"}
+	alpinePrefixes = []string{"x-", ":", "@"}
+)
+
+type attributePolicy struct {
+	names         []string
+	prefixes      []string
+	eventHandlers bool
+}
+
+func (p *attributePolicy) allows(name string) bool {
+	if isEventHandler(name) {
+		return p.eventHandlers
+	}
+
+	if slices.Contains(attributes, name) || slices.Contains(p.names, name) {
+		return true
+	}
+
+	if isAria(name) || isData(name) {
+		return true
+	}
+
+	return slices.ContainsFunc(p.prefixes, func(prefix string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+var policy = struct {
+	sync.RWMutex
+	*attributePolicy
+}{
+	attributePolicy: &attributePolicy{},
+}
+
+type AttributePolicyOption func(*attributePolicy)
+
+// AllowAttributes accepts extra attribute names
+func AllowAttributes(names ...string) AttributePolicyOption {
+	return func(p *attributePolicy) {
+		for _, name := range names {
+			p.names = append(p.names, normalizeAttributeName(name))
+		}
+	}
+}
+
+// AllowAttributePrefixes accepts any attribute starting with one of the prefixes
+func AllowAttributePrefixes(prefixes ...string) AttributePolicyOption {
+	return func(p *attributePolicy) {
+		for _, prefix := range prefixes {
+			p.prefixes = append(p.prefixes, normalizeAttributeName(prefix))
+		}
+	}
+}
+
+// AllowHTMX accepts htmx attributes (hx-*)
+func AllowHTMX() AttributePolicyOption {
+	return AllowAttributePrefixes(htmxPrefixes...)
+}
+
+// AllowAlpine accepts Alpine.js attributes (x-*, :* and @*)
+func AllowAlpine() AttributePolicyOption {
+	return AllowAttributePrefixes(alpinePrefixes...)
+}
+
+// AllowEventHandlers accepts event handler attributes (onclick, onchange...),
+// they are bound by a script rendered with the nonce of the form rather than inline
+func AllowEventHandlers() AttributePolicyOption {
+	return func(p *attributePolicy) {
+		p.eventHandlers = true
+	}
+}
+
+// SetAttributePolicy replaces the attribute policy with the built-in allowlist extended by the options
+func SetAttributePolicy(options ...AttributePolicyOption) {
+	p := &attributePolicy{}
+	for _, option := range options {
+		option(p)
+	}
+
+	policy.Lock()
+	defer policy.Unlock()
+
+	policy.attributePolicy = p
+}
+
+func isAllowedAttribute(name string) bool {
+	policy.RLock()
+	defer policy.RUnlock()
+
+	return policy.allows(name)
+}
+
+func normalizeAttributeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
 func isAria(name string) bool {
 	return strings.HasPrefix(name, "aria-")
 }
@@ -75,31 +189,101 @@ func isData(name string) bool {
 type attrModifier func(attrs Attrs)
 
 func newModifier(name string, value any) attrModifier {
-	n := strings.ToLower(strings.TrimSpace(name))
+	n := normalizeAttributeName(name)
 
-	if !slices.Contains(attributes, n) && !isAria(n) && !isData(n) {
+	if !isAllowedAttribute(n) {
+		if isEventHandler(n) {
+			panic(fmt.Sprintf("unsupported attribute %s, event handlers must be enabled with AllowEventHandlers", n))
+		}
 		panic(fmt.Sprintf("unsupported attribute %s", n))
 	}
 
 	switch n {
 	case "id":
-		return Id(value.(string))
+		return Id(formatAttributeValue(n, "", value).(string))
 	default:
 		return func(attrs Attrs) {
-			switch value := value.(type) {
-			case bool:
-				attrs[n] = value
-			case string:
-				attrs[n] = strings.TrimSpace(value)
-			default:
-				panic(
-					fmt.Sprintf("unsupported attribute %s type %T, only booleans & strings are supported", n, value),
-				)
-			}
+			attrs[n] = formatAttributeValue(n, attrs.String("type"), value)
 		}
 	}
 }
 
+func formatAttributeValue(name, inputType string, value any) any {
+	switch value := value.(type) {
+	case bool:
+		return value
+	case string:
+		return strings.TrimSpace(value)
+	case int:
+		return strconv.FormatInt(int64(value), 10)
+	case int8:
+		return strconv.FormatInt(int64(value), 10)
+	case int16:
+		return strconv.FormatInt(int64(value), 10)
+	case int32:
+		return strconv.FormatInt(int64(value), 10)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case uint:
+		return strconv.FormatUint(uint64(value), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(value), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(value), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(value), 10)
+	case uint64:
+		return strconv.FormatUint(value, 10)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case time.Time:
+		return formatTime(inputType, value)
+	case fmt.Stringer:
+		return strings.TrimSpace(value.String())
+	}
+
+	// named types such as type Quantity int
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return strings.TrimSpace(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	default:
+		panic(
+			fmt.Sprintf("unsupported attribute %s type %T, only booleans, strings, numbers, times & fmt.Stringer are supported", name, value),
+		)
+	}
+}
+
+func formatTime(inputType string, t time.Time) string {
+	switch inputType {
+	case InputTypeDate:
+		return t.Format(time.DateOnly)
+	case InputTypeDateTimeLocal:
+		return t.Format("2006-01-02T15:04")
+	case InputTypeTime:
+		return t.Format("15:04")
+	case InputTypeMonth:
+		return t.Format("2006-01")
+	case InputTypeWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
+
 type Attrs map[string]any
 
 func (a Attrs) Set(name string, value any) Attrs {
@@ -108,6 +292,55 @@ func (a Attrs) Set(name string, value any) Attrs {
 	return a
 }
 
+func (a Attrs) AddTokens(name string, tokens ...string) Attrs {
+	list := strings.Fields(a.String(name))
+	for _, token := range tokens {
+		for _, t := range strings.Fields(token) {
+			if !slices.Contains(list, t) {
+				list = append(list, t)
+			}
+		}
+	}
+	return a.setTokens(name, list)
+}
+
+func (a Attrs) RemoveTokens(name string, tokens ...string) Attrs {
+	remove := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		remove = append(remove, strings.Fields(token)...)
+	}
+
+	list := slices.DeleteFunc(strings.Fields(a.String(name)), func(t string) bool {
+		return slices.Contains(remove, t)
+	})
+	return a.setTokens(name, list)
+}
+
+func (a Attrs) ToggleTokens(name string, tokens ...string) Attrs {
+	list := strings.Fields(a.String(name))
+	for _, token := range tokens {
+		for _, t := range strings.Fields(token) {
+			if i := slices.Index(list, t); i >= 0 {
+				list = slices.Delete(list, i, i+1)
+			} else {
+				list = append(list, t)
+			}
+		}
+	}
+	return a.setTokens(name, list)
+}
+
+func (a Attrs) HasToken(name, token string) bool {
+	return slices.Contains(strings.Fields(a.String(name)), token)
+}
+
+func (a Attrs) setTokens(name string, tokens []string) Attrs {
+	if len(tokens) == 0 {
+		return a.Unset(normalizeAttributeName(name))
+	}
+	return a.Set(name, strings.Join(tokens, " "))
+}
+
 func (a Attrs) Unset(name string) Attrs {
 	delete(a, name)
 	return a
@@ -147,6 +380,7 @@ func Attributes(modifiers ...attrModifier) Attrs {
 
 func Id(id string) attrModifier {
 	return func(attrs Attrs) {
+		previous := attrs.String("id")
 		attrs["id"] = id
 
 		// make sure ID-depend attributes are kept up to date
@@ -155,9 +389,17 @@ func Id(id string) attrModifier {
 			attrs[AriaErrorAttribute] = fmt.Sprintf(AriaErrorTemplate, id)
 		}
 
-		_, hasHint := attrs[AriaHintAttribute]
-		if hasHint {
-			attrs[AriaHintAttribute] = fmt.Sprintf(AriaHintTemplate, id)
+		tokens := strings.Fields(attrs.String(AriaDescribedByAttribute))
+		for i, token := range tokens {
+			switch token {
+			case fmt.Sprintf(AriaHintTemplate, previous):
+				tokens[i] = fmt.Sprintf(AriaHintTemplate, id)
+			case fmt.Sprintf(AriaErrorTemplate, previous):
+				tokens[i] = fmt.Sprintf(AriaErrorTemplate, id)
+			}
+		}
+		if len(tokens) > 0 {
+			attrs[AriaDescribedByAttribute] = strings.Join(tokens, " ")
 		}
 	}
 }
@@ -166,15 +408,37 @@ func Attr(name string, value any) attrModifier {
 	return newModifier(name, value)
 }
 
-func GenId() string {
-	rand.NewSource(time.Now().UnixNano())
+func AddClass(classes ...string) attrModifier {
+	return func(attrs Attrs) {
+		attrs.AddTokens("class", classes...)
+	}
+}
+
+func RemoveClass(classes ...string) attrModifier {
+	return func(attrs Attrs) {
+		attrs.RemoveTokens("class", classes...)
+	}
+}
+
+func ToggleClass(classes ...string) attrModifier {
+	return func(attrs Attrs) {
+		attrs.ToggleTokens("class", classes...)
+	}
+}
 
+func AddDescribedBy(ids ...string) attrModifier {
+	return func(attrs Attrs) {
+		attrs.AddTokens(AriaDescribedByAttribute, ids...)
+	}
+}
+
+func GenId() string {
 	l := 10
 	c := "abcdefghijklmnopqrstuvwxyz0123456789"
 
 	b := make([]byte, l)
 	for i := range b {
-		b[i] = c[rand.Intn(len(c))] //nolint:gosec // G404: Use of weak random number generator
+		b[i] = c[rand.IntN(len(c))] //nolint:gosec // G404: Use of weak random number generator
 	}
 	return string(b)
 }
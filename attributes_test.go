@@ -3,6 +3,7 @@ package goform
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewModifier_ValidAttributes(t *testing.T) {
@@ -255,10 +256,138 @@ func TestNewModifier_UnsupportedType(t *testing.T) {
 	}()
 
 	attrs := make(Attrs)
-	modifier := newModifier("class", 123) // int is unsupported
+	modifier := newModifier("class", []string{"a"}) // slices are unsupported
 	modifier(attrs)
 }
 
+type quantity int
+
+type ratio float64
+
+type label string
+
+func TestNewModifier_RichTypes(t *testing.T) {
+	date := time.Date(2024, time.March, 5, 14, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		attr     string
+		kind     string
+		value    any
+		expected string
+	}{
+		{"int", "maxlength", InputTypeText, 50, "50"},
+		{"int64", "max", InputTypeNumber, int64(-12), "-12"},
+		{"uint8", "tabindex", InputTypeText, uint8(3), "3"},
+		{"float64", "step", InputTypeNumber, 0.5, "0.5"},
+		{"float32", "step", InputTypeNumber, float32(0.25), "0.25"},
+		{"stringer", "title", InputTypeText, time.Duration(90) * time.Second, "1m30s"},
+		{"date", "min", InputTypeDate, date, "2024-03-05"},
+		{"datetime-local", "value", InputTypeDateTimeLocal, date, "2024-03-05T14:30"},
+		{"time", "max", InputTypeTime, date, "14:30"},
+		{"month", "value", InputTypeMonth, date, "2024-03"},
+		{"week", "value", InputTypeWeek, date, "2024-W10"},
+		{"other time", "data-at", InputTypeText, date, "2024-03-05T14:30:15Z"},
+		{"named int", "max", InputTypeNumber, quantity(3), "3"},
+		{"named float", "step", InputTypeNumber, ratio(0.5), "0.5"},
+		{"named string", "title", InputTypeText, label(" Quantity "), "Quantity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := Attributes(Attr("type", tt.kind), Attr(tt.attr, tt.value))
+
+			if attrs.String(tt.attr) != tt.expected {
+				t.Errorf("expected %s=%s, got %v", tt.attr, tt.expected, attrs[tt.attr])
+			}
+		})
+	}
+
+	t.Run("renders formatted values", func(t *testing.T) {
+		elem := Number("quantity").SetAttributes(Id("quantity"), Attr("min", 1), Attr("step", 0.5))
+		expected := `<div><div><input id="quantity" min="1" name="quantity" step="0.5" type="number"></div></div>`
+
+		if result := cleanHTML(elem.Render()); result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, result)
+		}
+	})
+}
+
+func TestAttrs_Tokens(t *testing.T) {
+	t.Run("adds tokens once", func(t *testing.T) {
+		attrs := Attributes(Attr("class", "a"))
+		attrs.AddTokens("class", "b c", "a", "", "d")
+
+		if attrs.String("class") != "a b c d" {
+			t.Errorf("expected a b c d, got %s", attrs.String("class"))
+		}
+	})
+
+	t.Run("removes tokens", func(t *testing.T) {
+		attrs := Attributes(Attr("class", "a b c"))
+		attrs.RemoveTokens("class", "b", "x")
+
+		if attrs.String("class") != "a c" {
+			t.Errorf("expected a c, got %s", attrs.String("class"))
+		}
+
+		attrs.RemoveTokens("class", "a c")
+		if _, ok := attrs["class"]; ok {
+			t.Error("expected empty token list to remove the attribute")
+		}
+	})
+
+	t.Run("toggles tokens", func(t *testing.T) {
+		attrs := Attributes(Attr("class", "a b"))
+		attrs.ToggleTokens("class", "b", "c")
+
+		if attrs.String("class") != "a c" {
+			t.Errorf("expected a c, got %s", attrs.String("class"))
+		}
+	})
+
+	t.Run("reports tokens", func(t *testing.T) {
+		attrs := Attributes(Attr("class", "a b"))
+
+		if !attrs.HasToken("class", "b") || attrs.HasToken("class", "c") {
+			t.Error("expected HasToken to match whole tokens")
+		}
+	})
+
+	t.Run("applies the attribute policy", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for unsupported attribute")
+			}
+		}()
+		Attributes().AddTokens("unsupported-attr", "a")
+	})
+}
+
+func TestClassModifiers(t *testing.T) {
+	attrs := Attributes(
+		Attr("class", "btn"),
+		AddClass("btn-primary", "active"),
+		RemoveClass("active"),
+		ToggleClass("disabled", "btn"),
+	)
+
+	if attrs.String("class") != "btn-primary disabled" {
+		t.Errorf("expected btn-primary disabled, got %s", attrs.String("class"))
+	}
+}
+
+func TestAddDescribedBy(t *testing.T) {
+	attrs := Attributes(
+		Attr("aria-describedby", "first"),
+		AddDescribedBy("second", "first"),
+	)
+
+	if attrs.String("aria-describedby") != "first second" {
+		t.Errorf("expected first second, got %s", attrs.String("aria-describedby"))
+	}
+}
+
 func TestAttrs_Set(t *testing.T) {
 	attrs := make(Attrs)
 	result := attrs.Set("class", "test-class")
@@ -346,8 +475,9 @@ func TestAttributes(t *testing.T) {
 
 func TestId(t *testing.T) {
 	attrs := make(Attrs)
+	attrs.Set("id", "foo")
 	attrs.Set("aria-errormessage", "foo-error")
-	attrs.Set("aria-describedby", "foo-hint")
+	attrs.Set("aria-describedby", "custom foo-hint foo-error")
 	modifier := Id("test-id")
 	modifier(attrs)
 
@@ -357,8 +487,8 @@ func TestId(t *testing.T) {
 	if attrs["aria-errormessage"] != "test-id-error" {
 		t.Errorf("expected aria-errormessage=test-id-error, got %v", attrs["aria-errormessage"])
 	}
-	if attrs["aria-describedby"] != "test-id-hint" {
-		t.Errorf("expected aria-describedby=test-id-hint, got %v", attrs["aria-describedby"])
+	if attrs["aria-describedby"] != "custom test-id-hint test-id-error" {
+		t.Errorf("expected aria-describedby=custom test-id-hint test-id-error, got %v", attrs["aria-describedby"])
 	}
 }
 
@@ -460,3 +590,173 @@ func TestIsData(t *testing.T) {
 		})
 	}
 }
+
+func TestAttributePolicy(t *testing.T) {
+	t.Cleanup(func() {
+		SetAttributePolicy()
+	})
+
+	allowed := func(name string) (ok bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				ok = false
+			}
+		}()
+		newModifier(name, "value")
+		return true
+	}
+
+	t.Run("accepts standard attributes", func(t *testing.T) {
+		SetAttributePolicy()
+
+		for _, name := range []string{"inputmode", "enterkeyhint", "form", "formaction", "popovertarget", "nonce"} {
+			if !allowed(name) {
+				t.Errorf("expected %s to be allowed", name)
+			}
+		}
+	})
+
+	t.Run("rejects framework attributes by default", func(t *testing.T) {
+		SetAttributePolicy()
+
+		for _, name := range []string{"hx-post", "x-model", "@click", ":class", "ng-model"} {
+			if allowed(name) {
+				t.Errorf("expected %s to be rejected", name)
+			}
+		}
+	})
+
+	t.Run("accepts registered names and prefixes", func(t *testing.T) {
+		SetAttributePolicy(
+			AllowAttributes(" Autocapitalize "),
+			AllowAttributePrefixes("ng-"),
+		)
+
+		for _, name := range []string{"autocapitalize", "ng-model", "ng-if"} {
+			if !allowed(name) {
+				t.Errorf("expected %s to be allowed", name)
+			}
+		}
+		if allowed("hx-post") {
+			t.Error("expected hx-post to be rejected")
+		}
+	})
+
+	t.Run("htmx preset", func(t *testing.T) {
+		SetAttributePolicy(AllowHTMX())
+
+		for _, name := range []string{"hx-post", "hx-target", "hx-swap"} {
+			if !allowed(name) {
+				t.Errorf("expected %s to be allowed", name)
+			}
+		}
+		if allowed("x-model") {
+			t.Error("expected x-model to be rejected")
+		}
+	})
+
+	t.Run("alpine preset", func(t *testing.T) {
+		SetAttributePolicy(AllowAlpine())
+
+		for _, name := range []string{"x-model", "x-data", "@click", ":class"} {
+			if !allowed(name) {
+				t.Errorf("expected %s to be allowed", name)
+			}
+		}
+	})
+
+	t.Run("rejects event handlers by default", func(t *testing.T) {
+		SetAttributePolicy(AllowAttributePrefixes("on"), AllowAttributes("onclick"))
+
+		if allowed("onclick") {
+			t.Error("expected onclick to be rejected")
+		}
+
+		defer func() {
+			r := recover()
+			if r == nil || !strings.Contains(r.(string), "AllowEventHandlers") {
+				t.Errorf("expected panic mentioning AllowEventHandlers, got %v", r)
+			}
+		}()
+		newModifier("onchange", "submit()")
+	})
+
+	t.Run("accepts event handlers when enabled", func(t *testing.T) {
+		SetAttributePolicy(AllowEventHandlers())
+
+		if !allowed("onchange") {
+			t.Error("expected onchange to be allowed")
+		}
+	})
+
+	t.Run("only rejects real event handlers", func(t *testing.T) {
+		SetAttributePolicy(AllowAttributes("one", "onboarding"))
+
+		for _, name := range []string{"one", "onboarding"} {
+			if !allowed(name) {
+				t.Errorf("expected %s to be allowed", name)
+			}
+		}
+		if allowed("onclick") {
+			t.Error("expected onclick to be rejected")
+		}
+	})
+
+	t.Run("binds event handlers with a nonce'd script", func(t *testing.T) {
+		SetAttributePolicy(AllowEventHandlers())
+
+		f := Form(WithNonce("r4nd0m")).
+			SetAttributes(Id("filters")).
+			AddChildren(Select("sort").SetAttributes(Id("sort"), Attr("onchange", "this.form.requestSubmit()")))
+
+		expected := `<select id="sort" name="sort">`
+		result := cleanHTML(f.Render())
+		if !strings.Contains(result, expected) || strings.Contains(result, `onchange=`) {
+			t.Errorf("expected the handler to be left out of the element, got %s", result)
+		}
+
+		expected = `<script nonce="r4nd0m">document.getElementById("sort")?.addEventListener("change", function (event) { this.form.requestSubmit() });</script></form>`
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected the binding script:\nExpected: %s\nActual:   %s", expected, result)
+		}
+	})
+
+	t.Run("binds the handlers of a radio group to each option", func(t *testing.T) {
+		SetAttributePolicy(AllowEventHandlers())
+
+		f := Form().AddChildren(
+			Radio("color").SetAttributes(Id("c"), Attr("onchange", "preview(this.value)")).SetOptions(
+				Option("Red", "red"),
+				Option("Blue", "blue"),
+			),
+		)
+
+		result := cleanHTML(f.Render())
+		for _, id := range []string{"c-0", "c-1"} {
+			expected := `document.getElementById("` + id + `")?.addEventListener("change", function (event) { preview(this.value) });`
+			if !strings.Contains(result, expected) {
+				t.Errorf("expected the handler to be bound to %s, got %s", id, result)
+			}
+		}
+		if strings.Contains(result, `getElementById("c")`) {
+			t.Errorf("expected the unrendered group id to be left out, got %s", result)
+		}
+	})
+
+	t.Run("renders no script without event handlers", func(t *testing.T) {
+		if result := cleanHTML(Form().AddChildren(Text("q")).Render()); strings.Contains(result, "<script") {
+			t.Errorf("expected no script, got %s", result)
+		}
+	})
+
+	t.Run("renders allowed attributes", func(t *testing.T) {
+		SetAttributePolicy(AllowHTMX())
+
+		elem := Text("q").SetAttributes(Id("q"), Attr("hx-get", "/search"), Attr("hx-trigger", "keyup"))
+		expected := `<div><div><input hx-get="/search" hx-trigger="keyup" id="q" name="q" type="text"></div></div>`
+
+		if result := cleanHTML(elem.Render()); result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, result)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package goform
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	AuditMissingLabel             = "missing-label"
+	AuditRadioWithoutLegend       = "radio-without-legend"
+	AuditImageWithoutAlt          = "image-without-alt"
+	AuditDuplicateId              = "duplicate-id"
+	AuditMultipleAutofocus        = "multiple-autofocus"
+	AuditRequiredWithoutIndicator = "required-without-indicator"
+)
+
+type AuditIssue struct {
+	Rule    string
+	Name    string
+	Id      string
+	Message string
+}
+
+func (i AuditIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Rule, i.Message)
+}
+
+func isUnlabelled(t string) bool {
+	return t == InputTypeHidden ||
+		t == InputTypeSubmit ||
+		t == InputTypeButton ||
+		t == InputTypeReset ||
+		t == InputTypeImage
+}
+
+func hasAccessibleName(e *element) bool {
+	return e.Label() != "" ||
+		e.attributes.String("aria-label") != "" ||
+		e.attributes.String("aria-labelledby") != ""
+}
+
+func hasLegend(parents []Renderer) bool {
+	return slices.ContainsFunc(parents, func(p Renderer) bool {
+		fs, ok := p.(*fieldSet)
+		return ok && fs.Legend() != ""
+	})
+}
+
+func (f *form) Audit() []AuditIssue {
+	issues := make([]AuditIssue, 0)
+	ids := make(map[string]int)
+	autofocus := make([]*element, 0)
+
+	countId := func(id string) {
+		if id != "" {
+			ids[id]++
+		}
+	}
+
+	report := func(rule string, e *element, format string, args ...any) {
+		issues = append(issues, AuditIssue{
+			Rule:    rule,
+			Name:    e.Name(),
+			Id:      e.Id(),
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	countId(f.Id())
+
+	walk(f.children, nil, func(node Renderer, parents []Renderer) {
+		switch node := node.(type) {
+		case *fieldSet:
+			countId(node.attributes.String("id"))
+		case *group:
+			countId(node.attributes.String("id"))
+		case *element:
+			t := node.attributes.String("type")
+			isRadioSet := t == InputTypeRadio && len(node.Options()) > 0
+
+			if isRadioSet {
+				for i := range node.flatOptions() {
+					countId(fmt.Sprintf("%s-%d", node.Id(), i))
+				}
+			} else {
+				countId(node.Id())
+			}
+
+			if node.attributes.Bool("autofocus") {
+				autofocus = append(autofocus, node)
+			}
+
+			if isRadioSet && node.Label() == "" && !hasLegend(parents) {
+				report(AuditRadioWithoutLegend, node, "radio options %s are not grouped in a fieldset with a legend", node.Name())
+			}
+
+			if t == InputTypeImage && node.attributes.String("alt") == "" {
+				report(AuditImageWithoutAlt, node, "image input %s has no alt text", node.Name())
+			}
+
+			if !isUnlabelled(t) && !isRadioSet && !hasAccessibleName(node) {
+				report(AuditMissingLabel, node, "element %s has no label", node.Name())
+			}
+
+			if node.IsRequired() && !isUnlabelled(t) && node.Label() == "" {
+				report(AuditRequiredWithoutIndicator, node, "required element %s has no visible required indicator", node.Name())
+			}
+		}
+	})
+
+	for _, e := range autofocus[min(1, len(autofocus)):] {
+		report(AuditMultipleAutofocus, e, "element %s is autofocused, only one element should be", e.Name())
+	}
+
+	duplicates := make([]string, 0)
+	for id, count := range ids {
+		if count > 1 {
+			duplicates = append(duplicates, id)
+		}
+	}
+	slices.Sort(duplicates)
+
+	for _, id := range duplicates {
+		issues = append(issues, AuditIssue{
+			Rule:    AuditDuplicateId,
+			Id:      id,
+			Message: fmt.Sprintf("id %s is used %d times", id, ids[id]),
+		})
+	}
+
+	return issues
+}
@@ -0,0 +1,138 @@
+package goform
+
+import (
+	"testing"
+)
+
+func auditRules(issues []AuditIssue) map[string][]string {
+	rules := make(map[string][]string)
+	for _, issue := range issues {
+		key := issue.Name
+		if key == "" {
+			key = issue.Id
+		}
+		rules[issue.Rule] = append(rules[issue.Rule], key)
+	}
+	return rules
+}
+
+func TestForm_Audit(t *testing.T) {
+	t.Run("accessible form has no issues", func(t *testing.T) {
+		f := Form().AddChildren(
+			Text("username").SetLabel("Username").SetAttributes(Attr("required", true), Attr("autofocus", true)),
+			Search("q").SetAttributes(Attr("aria-label", "Search")),
+			FieldSet("Plan", Radio("plan").SetOptions(Option("Free", "free"), Option("Pro", "pro"))),
+			Image("go").SetAttributes(Attr("alt", "Go")),
+			Hidden("token"),
+			Group(Submit("save"), Reset("reset")),
+		)
+
+		if issues := f.Audit(); len(issues) != 0 {
+			t.Errorf("expected no issues, got %v", issues)
+		}
+	})
+
+	t.Run("reports missing labels", func(t *testing.T) {
+		f := Form().AddChildren(
+			Text("username"),
+			Group(Textarea("bio"), Select("country")),
+			Checkbox("terms"),
+		)
+
+		rules := auditRules(f.Audit())
+		expected := []string{"username", "bio", "country", "terms"}
+		if len(rules[AuditMissingLabel]) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, rules[AuditMissingLabel])
+		}
+		for i, name := range expected {
+			if rules[AuditMissingLabel][i] != name {
+				t.Errorf("expected %s at %d, got %s", name, i, rules[AuditMissingLabel][i])
+			}
+		}
+	})
+
+	t.Run("reports radio options outside a fieldset with a legend", func(t *testing.T) {
+		f := Form().AddChildren(
+			Radio("plan").SetOptions(Option("Free", "free")),
+			FieldSet("", Radio("size").SetOptions(Option("Small", "s"))),
+			FieldSet("Colour", Group(Radio("colour").SetOptions(Option("Red", "red")))),
+			Radio("shipping").SetLabel("Shipping").SetOptions(Option("Standard", "standard")),
+		)
+
+		rules := auditRules(f.Audit())
+		if len(rules[AuditRadioWithoutLegend]) != 2 ||
+			rules[AuditRadioWithoutLegend][0] != "plan" ||
+			rules[AuditRadioWithoutLegend][1] != "size" {
+			t.Errorf("expected plan and size, got %v", rules[AuditRadioWithoutLegend])
+		}
+	})
+
+	t.Run("reports image inputs without alt", func(t *testing.T) {
+		f := Form().AddChildren(Image("submit"))
+
+		rules := auditRules(f.Audit())
+		if len(rules[AuditImageWithoutAlt]) != 1 {
+			t.Errorf("expected one issue, got %v", rules[AuditImageWithoutAlt])
+		}
+	})
+
+	t.Run("reports duplicate ids", func(t *testing.T) {
+		f := Form().SetAttributes(Id("login")).AddChildren(
+			Text("username").SetLabel("Username").SetAttributes(Id("login")),
+			FieldSet("Details", Email("email").SetLabel("Email").SetAttributes(Id("contact"))).
+				SetAttributes(Id("contact")),
+			FieldSet("Plan", Radio("plan").SetAttributes(Id("plan")).SetOptions(Option("Free", "free"))),
+			Hidden("plan-0").SetAttributes(Id("plan-0")),
+		)
+
+		rules := auditRules(f.Audit())
+		expected := []string{"contact", "login", "plan-0"}
+		if len(rules[AuditDuplicateId]) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, rules[AuditDuplicateId])
+		}
+		for i, id := range expected {
+			if rules[AuditDuplicateId][i] != id {
+				t.Errorf("expected %s at %d, got %s", id, i, rules[AuditDuplicateId][i])
+			}
+		}
+	})
+
+	t.Run("reports multiple autofocus", func(t *testing.T) {
+		f := Form().AddChildren(
+			Text("first").SetLabel("First").SetAttributes(Attr("autofocus", true)),
+			Text("second").SetLabel("Second").SetAttributes(Attr("autofocus", true)),
+			Text("third").SetLabel("Third").SetAttributes(Attr("autofocus", true)),
+		)
+
+		rules := auditRules(f.Audit())
+		if len(rules[AuditMultipleAutofocus]) != 2 ||
+			rules[AuditMultipleAutofocus][0] != "second" ||
+			rules[AuditMultipleAutofocus][1] != "third" {
+			t.Errorf("expected second and third, got %v", rules[AuditMultipleAutofocus])
+		}
+	})
+
+	t.Run("reports required fields without a visible indicator", func(t *testing.T) {
+		f := Form().AddChildren(
+			Text("q").SetAttributes(Attr("aria-label", "Search"), Attr("required", true)),
+			Text("name").SetLabel("Name").SetAttributes(Attr("required", true)),
+			Hidden("token").SetAttributes(Attr("required", true)),
+		)
+
+		rules := auditRules(f.Audit())
+		if len(rules[AuditRequiredWithoutIndicator]) != 1 || rules[AuditRequiredWithoutIndicator][0] != "q" {
+			t.Errorf("expected q, got %v", rules[AuditRequiredWithoutIndicator])
+		}
+		if len(rules[AuditMissingLabel]) != 0 {
+			t.Errorf("expected aria-label to count as a label, got %v", rules[AuditMissingLabel])
+		}
+	})
+}
+
+func TestAuditIssue_String(t *testing.T) {
+	issue := AuditIssue{Rule: AuditMissingLabel, Name: "username", Message: "element username has no label"}
+
+	if issue.String() != "missing-label: element username has no label" {
+		t.Errorf("unexpected string %s", issue.String())
+	}
+}
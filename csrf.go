@@ -0,0 +1,203 @@
+package goform
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	CSRFFieldName  = "csrf_token"
+	CSRFCookieName = "csrf_token"
+)
+
+var (
+	ErrInvalidCSRFToken = errors.New("invalid csrf token")
+	ErrMissingSessionId = errors.New("session id is missing")
+	ErrMissingCSRFKey   = errors.New("csrf key is missing")
+	ErrCSRFTokenNotSet  = errors.New("csrf token was not issued")
+)
+
+// CSRFError is returned by PopulateFromRequest when the submitted token cannot be verified
+type CSRFError struct {
+	Reason string
+}
+
+func (e *CSRFError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidCSRFToken, e.Reason)
+}
+
+func (e *CSRFError) Unwrap() error {
+	return ErrInvalidCSRFToken
+}
+
+// CSRFTokenStore issues the tokens rendered in forms and verifies the submitted ones
+type CSRFTokenStore interface {
+	// Token returns the token of the request, the response can be used to persist it
+	Token(w http.ResponseWriter, r *http.Request) (string, error)
+	// Verify reports whether the submitted token is valid for the request
+	Verify(r *http.Request, token string) bool
+}
+
+// WithCSRF renders a hidden token field in the form and verifies it in PopulateFromRequest
+func WithCSRF(store CSRFTokenStore) FormOption {
+	return func(options *formOptions) {
+		options.csrf = store
+	}
+}
+
+// CookieTokenStore implements the double-submit cookie pattern,
+// the token is stored in a cookie and must be submitted back with the form
+type CookieTokenStore struct {
+	CookieName string
+	Path       string
+	Domain     string
+	Secure     bool
+	SameSite   http.SameSite
+}
+
+func (s *CookieTokenStore) cookieName() string {
+	if s.CookieName != "" {
+		return s.CookieName
+	}
+	return CSRFCookieName
+}
+
+func (s *CookieTokenStore) Token(w http.ResponseWriter, r *http.Request) (string, error) {
+	if cookie, err := r.Cookie(s.cookieName()); err == nil && cookie.Value != "" {
+		return cookie.Value, nil
+	}
+
+	token := rand.Text()
+
+	path := s.Path
+	if path == "" {
+		path = "/"
+	}
+	sameSite := s.SameSite
+	if sameSite == 0 {
+		sameSite = http.SameSiteLaxMode
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.cookieName(),
+		Value:    token,
+		Path:     path,
+		Domain:   s.Domain,
+		Secure:   s.Secure,
+		HttpOnly: true,
+		SameSite: sameSite,
+	})
+
+	return token, nil
+}
+
+func (s *CookieTokenStore) Verify(r *http.Request, token string) bool {
+	cookie, err := r.Cookie(s.cookieName())
+	if err != nil || cookie.Value == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(token)) == 1
+}
+
+// HMACTokenStore issues tokens bound to the session of the request,
+// a token is a random nonce signed together with the session id
+type HMACTokenStore struct {
+	Key       []byte
+	SessionId func(r *http.Request) (string, error)
+}
+
+func (s *HMACTokenStore) sign(session, nonce string) string {
+	mac := hmac.New(sha256.New, s.Key)
+	mac.Write([]byte(session))
+	mac.Write([]byte{0})
+	mac.Write([]byte(nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *HMACTokenStore) Token(_ http.ResponseWriter, r *http.Request) (string, error) {
+	if len(s.Key) == 0 {
+		return "", ErrMissingCSRFKey
+	}
+
+	session, err := s.SessionId(r)
+	if err != nil {
+		return "", err
+	}
+	if session == "" {
+		return "", ErrMissingSessionId
+	}
+
+	nonce := rand.Text()
+	return nonce + "." + s.sign(session, nonce), nil
+}
+
+func (s *HMACTokenStore) Verify(r *http.Request, token string) bool {
+	if len(s.Key) == 0 {
+		return false
+	}
+
+	session, err := s.SessionId(r)
+	if err != nil || session == "" {
+		return false
+	}
+
+	nonce, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.sign(session, nonce)))
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// IssueCSRFToken sets the token rendered in the form, it must be called before rendering a form protected WithCSRF
+func (f *form) IssueCSRFToken(w http.ResponseWriter, r *http.Request) error {
+	if f.options.csrf == nil {
+		return nil
+	}
+
+	token, err := f.options.csrf.Token(w, r)
+	if err != nil {
+		return fmt.Errorf("failed to issue csrf token: %w", err)
+	}
+
+	f.csrf.SetValue(token)
+	return nil
+}
+
+// verifyCSRFIssued prevents rendering a form whose token field is empty, its submissions would always be rejected
+func (f *form) verifyCSRFIssued() error {
+	if f.csrf != nil && f.csrf.Value() == "" {
+		return fmt.Errorf("%w: call IssueCSRFToken before rendering the form", ErrCSRFTokenNotSet)
+	}
+	return nil
+}
+
+func (f *form) verifyCSRFToken(r *http.Request, submitted url.Values) error {
+	if f.options.csrf == nil || isSafeMethod(r.Method) {
+		return nil
+	}
+
+	token := submitted.Get(CSRFFieldName)
+	if token == "" {
+		return &CSRFError{Reason: "token is missing"}
+	}
+	if !f.options.csrf.Verify(r, token) {
+		return &CSRFError{Reason: "token does not match"}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package goform
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func postForm(values url.Values, cookies ...*http.Cookie) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", URLEncodedData)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
+func TestForm_CSRFCookieTokenStore(t *testing.T) {
+	store := &CookieTokenStore{Secure: true}
+
+	// issue a token on GET
+	rec := httptest.NewRecorder()
+	f := Form(WithCSRF(store)).AddChildren(Text("name"))
+	if err := f.IssueCSRFToken(rec, httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != CSRFCookieName || !cookies[0].HttpOnly || !cookies[0].Secure {
+		t.Fatalf("expected a secure http only cookie, got %v", cookies)
+	}
+	token := cookies[0].Value
+
+	expected := `<input name="csrf_token" type="hidden" value="` + token + `">`
+	if result := cleanHTML(f.Render()); !strings.Contains(result, expected) {
+		t.Errorf("expected the token field in %s", result)
+	}
+
+	t.Run("reuses the cookie token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+
+		rec := httptest.NewRecorder()
+		other := Form(WithCSRF(store))
+		if err := other.IssueCSRFToken(rec, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rec.Result().Cookies()) != 0 || other.csrf.Value() != token {
+			t.Error("expected the cookie token to be reused")
+		}
+	})
+
+	t.Run("accepts the matching token", func(t *testing.T) {
+		f := Form(WithCSRF(store)).AddChildren(Text("name"))
+		req := postForm(url.Values{CSRFFieldName: {token}, "name": {"Jane"}}, cookies[0])
+
+		if err := f.PopulateFromRequest(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v := f.Elements()["name"].Value(); v != "Jane" {
+			t.Errorf("expected name=Jane, got %s", v)
+		}
+	})
+
+	t.Run("rejects missing and mismatching tokens", func(t *testing.T) {
+		requests := map[string]*http.Request{
+			"missing token":  postForm(url.Values{"name": {"Jane"}}, cookies[0]),
+			"missing cookie": postForm(url.Values{CSRFFieldName: {token}, "name": {"Jane"}}),
+			"mismatch":       postForm(url.Values{CSRFFieldName: {"forged"}, "name": {"Jane"}}, cookies[0]),
+		}
+
+		for name, req := range requests {
+			f := Form(WithCSRF(store)).AddChildren(Text("name"))
+			err := f.PopulateFromRequest(req)
+
+			var csrfErr *CSRFError
+			if !errors.As(err, &csrfErr) || !errors.Is(err, ErrInvalidCSRFToken) {
+				t.Errorf("%s: expected a CSRFError, got %v", name, err)
+			}
+			if v := f.Elements()["name"].Value(); v != "" {
+				t.Errorf("%s: expected the form not to be populated, got %s", name, v)
+			}
+		}
+	})
+
+	t.Run("safe methods are not verified", func(t *testing.T) {
+		f := Form(WithCSRF(store))
+		if err := f.PopulateFromRequest(httptest.NewRequest(http.MethodGet, "/?name=Jane", nil)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestForm_CSRFHMACTokenStore(t *testing.T) {
+	store := &HMACTokenStore{
+		Key: []byte("secret"),
+		SessionId: func(r *http.Request) (string, error) {
+			return r.Header.Get("X-Session"), nil
+		},
+	}
+
+	get := httptest.NewRequest(http.MethodGet, "/", nil)
+	get.Header.Set("X-Session", "alice")
+
+	f := Form(WithCSRF(store))
+	if err := f.IssueCSRFToken(httptest.NewRecorder(), get); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	token := f.csrf.Value()
+
+	t.Run("accepts the token of the session", func(t *testing.T) {
+		req := postForm(url.Values{CSRFFieldName: {token}})
+		req.Header.Set("X-Session", "alice")
+
+		if err := Form(WithCSRF(store)).PopulateFromRequest(req); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects the token of another session", func(t *testing.T) {
+		req := postForm(url.Values{CSRFFieldName: {token}})
+		req.Header.Set("X-Session", "mallory")
+
+		if err := Form(WithCSRF(store)).PopulateFromRequest(req); !errors.Is(err, ErrInvalidCSRFToken) {
+			t.Errorf("expected ErrInvalidCSRFToken, got %v", err)
+		}
+	})
+
+	t.Run("requires a session", func(t *testing.T) {
+		err := Form(WithCSRF(store)).IssueCSRFToken(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		if !errors.Is(err, ErrMissingSessionId) {
+			t.Errorf("expected ErrMissingSessionId, got %v", err)
+		}
+	})
+
+	t.Run("rejects an empty key", func(t *testing.T) {
+		unkeyed := &HMACTokenStore{SessionId: store.SessionId}
+
+		if err := Form(WithCSRF(unkeyed)).IssueCSRFToken(httptest.NewRecorder(), get); !errors.Is(err, ErrMissingCSRFKey) {
+			t.Errorf("expected ErrMissingCSRFKey, got %v", err)
+		}
+
+		req := postForm(url.Values{CSRFFieldName: {token}})
+		req.Header.Set("X-Session", "alice")
+		if unkeyed.Verify(req, token) {
+			t.Error("expected the token to be rejected without a key")
+		}
+	})
+}
+
+func TestForm_CSRFRenderRequiresToken(t *testing.T) {
+	f := Form(WithCSRF(&CookieTokenStore{})).AddChildren(Text("name"))
+
+	if _, err := f.RenderE(); !errors.Is(err, ErrCSRFTokenNotSet) {
+		t.Errorf("expected ErrCSRFTokenNotSet from RenderE, got %v", err)
+	}
+	if err := f.RenderTo(io.Discard); !errors.Is(err, ErrCSRFTokenNotSet) {
+		t.Errorf("expected ErrCSRFTokenNotSet from RenderTo, got %v", err)
+	}
+
+	if err := f.IssueCSRFToken(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := f.RenderE(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
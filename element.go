@@ -3,6 +3,8 @@ package goform
 import (
 	"fmt"
 	"html/template"
+	"io"
+	"slices"
 	"strings"
 )
 
@@ -37,6 +39,13 @@ const (
 	TextareaElement = "textarea"
 )
 
+// Codes returned by ValidationError
+const (
+	ValidationRequired         = "required"
+	ValidationInvalidOption    = "invalid-option"
+	ValidationInvalidSignature = "invalid-signature"
+)
+
 func isInputType(t string) bool {
 	return t != SelectElement && t != TextareaElement
 }
@@ -151,18 +160,6 @@ func Select(name string) *element {
 	return newElement(name, SelectElement)
 }
 
-func Option(label, value string) option {
-	return option{
-		Label: strings.TrimSpace(label),
-		Value: strings.TrimSpace(value),
-	}
-}
-
-type option struct {
-	Label string
-	Value string
-}
-
 type Element interface {
 	Renderer
 	HintRenderer
@@ -175,13 +172,20 @@ type Element interface {
 }
 
 type element struct {
-	hint       string
-	label      string
-	error      string
-	template   string
-	options    []option
-	attributes Attrs
-	renderer   TemplateRenderer
+	autoId           string
+	hint             string
+	label            string
+	error            string
+	template         string
+	custom           string
+	placeholder      string
+	values           []string
+	options          []option
+	keyring          *Keyring
+	signatureContext string
+	tampered         bool
+	attributes       Attrs
+	renderer         TemplateRenderer
 }
 
 func newElement(name, kind string) *element {
@@ -200,10 +204,12 @@ func newElement(name, kind string) *element {
 		a.Set("type", kind)
 	}
 
+	id := GenId()
 	a.Set("name", name).
-		Set("id", GenId())
+		Set("id", id)
 
 	i := &element{
+		autoId:     id,
 		template:   t,
 		attributes: a,
 		renderer:   getTemplateRenderer(),
@@ -212,8 +218,28 @@ func newElement(name, kind string) *element {
 	return i
 }
 
+func (e *element) templateName() string {
+	if e.custom != "" {
+		return e.custom
+	}
+	return fmt.Sprintf("%s.tmpl", e.template)
+}
+
+func (e *element) SetTemplate(name string) *element {
+	e.custom = strings.TrimSpace(name)
+	return e
+}
+
 func (e *element) Render() template.HTML {
-	return e.renderer.Render(fmt.Sprintf("%s.tmpl", e.template), e)
+	return e.renderer.Render(e.templateName(), e)
+}
+
+func (e *element) RenderE() (template.HTML, error) {
+	return e.renderer.RenderE(e.templateName(), e)
+}
+
+func (e *element) RenderTo(w io.Writer) error {
+	return e.renderer.RenderTo(w, e.templateName(), e)
 }
 
 func (e *element) RenderError() template.HTML {
@@ -249,6 +275,7 @@ func (e *element) Attribute(name string) any {
 }
 
 func (e *element) SetValue(value string) {
+	e.values = nil
 	e.attributes.Set("value", value)
 }
 
@@ -256,19 +283,80 @@ func (e *element) Value() string {
 	return e.attributes.String("value")
 }
 
+// SetValues sets the values of a multiple select, Value returns the first one
+func (e *element) SetValues(values ...string) {
+	first := ""
+	if len(values) > 0 {
+		first = values[0]
+	}
+	e.SetValue(first)
+	e.values = slices.Clone(values)
+}
+
+func (e *element) Values() []string {
+	if e.values != nil {
+		return e.values
+	}
+	if value := e.Value(); value != "" {
+		return []string{value}
+	}
+	return nil
+}
+
+func (e *element) IsMultiValued() bool {
+	return e.template == SelectElement && e.attributes.Bool("multiple")
+}
+
+// isAction reports whether the element is a button, its value is never bound
+func (e *element) isAction() bool {
+	switch e.attributes.String("type") {
+	case InputTypeSubmit, InputTypeReset, InputTypeButton, InputTypeImage:
+		return true
+	}
+	return false
+}
+
+// isLocked reports whether the element cannot be modified by the user
+func (e *element) isLocked() bool {
+	return e.attributes.Bool("disabled") || e.attributes.Bool("readonly")
+}
+
 func (e *element) IsRequired() bool {
 	return e.attributes.Bool("required")
 }
 
 func (e *element) IsValid() bool {
-	if !e.IsRequired() {
-		return true
+	return e.ValidationError() == ""
+}
+
+// ValidationError returns the code of the first failing validation rule, an empty string when the element is valid
+func (e *element) ValidationError() string {
+	if e.tampered {
+		return ValidationInvalidSignature
 	}
+
+	values := e.Values()
+
 	// @TODO check against a pattern if provided
-	if e.Value() != "" {
-		return true
+	if e.IsRequired() && len(values) == 0 {
+		return ValidationRequired
 	}
-	return false
+
+	if len(e.options) > 0 {
+		allowed := make([]string, 0, len(e.options))
+		for _, opt := range e.flatOptions() {
+			if !opt.Disabled {
+				allowed = append(allowed, opt.Value)
+			}
+		}
+		for _, value := range values {
+			if !slices.Contains(allowed, value) {
+				return ValidationInvalidOption
+			}
+		}
+	}
+
+	return ""
 }
 
 func (e *element) SetError(value string) *element {
@@ -278,6 +366,7 @@ func (e *element) SetError(value string) *element {
 	} else {
 		e.attributes.Set(AriaErrorAttribute, fmt.Sprintf(AriaErrorTemplate, e.Id()))
 	}
+	e.syncDescriptions()
 	return e
 }
 
@@ -294,13 +383,26 @@ func (e *element) Label() string {
 	return e.label
 }
 
+func (e *element) accessibleName() string {
+	if e.label != "" {
+		return e.label
+	}
+	if label := e.attributes.String("aria-label"); label != "" {
+		return label
+	}
+	return e.Name()
+}
+
+func (e *element) focusId() string {
+	if e.attributes.String("type") == InputTypeRadio && len(e.options) > 0 {
+		return fmt.Sprintf("%s-0", e.Id())
+	}
+	return e.Id()
+}
+
 func (e *element) SetHint(value string) *element {
 	e.hint = strings.TrimSpace(value)
-	if e.hint == "" {
-		e.attributes.Unset(AriaHintAttribute)
-	} else {
-		e.attributes.Set(AriaHintAttribute, fmt.Sprintf(AriaHintTemplate, e.Id()))
-	}
+	e.syncDescriptions()
 	return e
 }
 
@@ -315,12 +417,7 @@ func (e *element) SetOptions(options ...option) *element {
 	}
 
 	e.options = make([]option, len(options))
-	for i, opt := range options {
-		e.options[i] = option{
-			Label: opt.Label,
-			Value: opt.Value,
-		}
-	}
+	copy(e.options, options)
 	return e
 }
 
@@ -328,19 +425,64 @@ func (e *element) Options() []option {
 	return e.options
 }
 
+// SetPlaceholder renders an empty first option on select elements
+// and sets the placeholder attribute on the other elements
+func (e *element) SetPlaceholder(value string) *element {
+	if e.template != SelectElement {
+		e.attributes.Set("placeholder", value)
+		return e
+	}
+	e.placeholder = strings.TrimSpace(value)
+	return e
+}
+
+func (e *element) Placeholder() string {
+	if e.template != SelectElement {
+		return e.attributes.String("placeholder")
+	}
+	return e.placeholder
+}
+
 func (e *element) SetAttributes(modifiers ...attrModifier) *element {
 	for _, mod := range modifiers {
 		mod(e.attributes)
 	}
+	e.syncDescriptions()
 	return e
 }
 
+func (e *element) DescribedBy() []string {
+	return strings.Fields(e.attributes.String(AriaDescribedByAttribute))
+}
+
+func (e *element) syncDescriptions() {
+	hintId := fmt.Sprintf(AriaHintTemplate, e.Id())
+	errorId := fmt.Sprintf(AriaErrorTemplate, e.Id())
+
+	e.attributes.RemoveTokens(AriaDescribedByAttribute, hintId, errorId)
+
+	if e.hint != "" {
+		e.attributes.AddTokens(AriaDescribedByAttribute, hintId)
+	}
+	if e.error != "" {
+		e.attributes.AddTokens(AriaDescribedByAttribute, errorId)
+	}
+}
+
 func (e *element) Attributes() Attrs {
 	return e.attributes
 }
 
+func (e *element) IsInvalid() bool {
+	return e.error != "" || e.attributes.String("aria-invalid") == "true"
+}
+
 func (e *element) MarkAsInvalid() {
 	e.attributes.Set("aria-invalid", "true")
 }
 
+func (e *element) setRenderer(renderer TemplateRenderer) {
+	e.renderer = renderer
+}
+
 var _ Element = (*element)(nil)
@@ -1,8 +1,11 @@
 package goform
 
 import (
+	"errors"
+	"slices"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestIsInputType(t *testing.T) {
@@ -256,6 +259,64 @@ func TestElement_SetHint(t *testing.T) {
 	}
 }
 
+func TestElement_DescribedBy(t *testing.T) {
+	t.Run("merges hint, error and custom descriptions", func(t *testing.T) {
+		elem := Text("email").
+			SetAttributes(Id("email"), Attr("aria-describedby", "email-policy")).
+			SetHint("We never share it").
+			SetError("Email is invalid")
+
+		expected := "email-policy email-hint email-error"
+		if got := elem.Attributes().String("aria-describedby"); got != expected {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+		if !slices.Equal(elem.DescribedBy(), strings.Fields(expected)) {
+			t.Errorf("expected %v, got %v", strings.Fields(expected), elem.DescribedBy())
+		}
+	})
+
+	t.Run("keeps managed descriptions when attributes are overwritten", func(t *testing.T) {
+		elem := Text("email").
+			SetAttributes(Id("email")).
+			SetHint("We never share it").
+			SetAttributes(Attr("aria-describedby", "email-policy"), AddDescribedBy("email-format"))
+
+		expected := "email-policy email-format email-hint"
+		if got := elem.Attributes().String("aria-describedby"); got != expected {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+	})
+
+	t.Run("removes cleared descriptions", func(t *testing.T) {
+		elem := Text("email").
+			SetAttributes(Id("email")).
+			SetHint("We never share it").
+			SetError("Email is invalid").
+			SetHint("").
+			SetError("")
+
+		if _, ok := elem.Attributes()["aria-describedby"]; ok {
+			t.Errorf("expected aria-describedby to be removed, got %v", elem.Attributes()["aria-describedby"])
+		}
+	})
+
+	t.Run("follows id changes", func(t *testing.T) {
+		elem := Text("email").
+			SetAttributes(AddDescribedBy("email-policy")).
+			SetHint("We never share it").
+			SetError("Email is invalid").
+			SetAttributes(Id("contact-email"))
+
+		expected := "email-policy contact-email-hint contact-email-error"
+		if got := elem.Attributes().String("aria-describedby"); got != expected {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+		if got := elem.Attributes().String("aria-errormessage"); got != "contact-email-error" {
+			t.Errorf("expected contact-email-error, got %s", got)
+		}
+	})
+}
+
 func TestElement_SetOptions(t *testing.T) {
 	elem := Select("test")
 	opt1 := Option("Label 1", "value1")
@@ -337,7 +398,7 @@ func TestElement_RenderWithAttributes(t *testing.T) {
 	result := elem.Render()
 	htmlStr := cleanHTML(result)
 
-	expected := `<div><label for="username-field">Username <span>*</span></label><div><input aria-describedby="username-field-hint" aria-errormessage="username-field-error" class="form-control" id="username-field" name="username" placeholder="Enter username" required type="text"><span id="username-field-error">This field is required</span><i id="username-field-hint">Must be unique</i></div></div>`
+	expected := `<div><label for="username-field">Username <span>*</span></label><div><input aria-describedby="username-field-hint username-field-error" aria-errormessage="username-field-error" class="form-control" id="username-field" name="username" placeholder="Enter username" required type="text"><span id="username-field-error">This field is required</span><i id="username-field-hint">Must be unique</i></div></div>`
 
 	if htmlStr != expected {
 		t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, htmlStr)
@@ -369,13 +430,121 @@ func TestElement_RenderSelect(t *testing.T) {
 	result := elem.Render()
 	htmlStr := cleanHTML(result)
 
-	expected := `<div><div><select id="country-select" name="country"><option value="us" >United States</option><option value="ca" >Canada</option></select></div></div>`
+	expected := `<div><div><select id="country-select" name="country"><option value="us">United States</option><option value="ca">Canada</option></select></div></div>`
 
 	if htmlStr != expected {
 		t.Errorf("expected exact HTML match:\nExpected: %s\nActual: %s", expected, htmlStr)
 	}
 }
 
+func TestElement_RenderSelectOptions(t *testing.T) {
+	t.Run("renders groups, placeholder and disabled options", func(t *testing.T) {
+		elem := Select("country").
+			SetAttributes(Id("country"), Attr("required", true)).
+			SetPlaceholder("Choose…").
+			SetOptions(
+				OptGroup("Europe",
+					Option("France", "fr").SetAttributes(Attr("data-code", "33")),
+					Option("Germany", "de").SetDisabled(true),
+				),
+				OptGroup("Asia", Option("Japan", "jp")).SetDisabled(true),
+				Option("Other", "other"),
+			)
+
+		expected := `<div><div><select id="country" name="country" required>` +
+			`<option value="" selected>Choose…</option>` +
+			`<optgroup label="Europe"><option value="fr" data-code="33">France</option><option value="de" disabled>Germany</option></optgroup>` +
+			`<optgroup disabled label="Asia"><option value="jp">Japan</option></optgroup>` +
+			`<option value="other">Other</option>` +
+			`</select></div></div>`
+
+		if result := cleanHTML(elem.Render()); result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual: %s", expected, result)
+		}
+	})
+
+	t.Run("selects the value over the selected options", func(t *testing.T) {
+		elem := Select("size").
+			SetPlaceholder("Choose").
+			SetOptions(Option("Small", "s").SetChecked(true), OptGroup("Large", Option("XL", "xl")))
+
+		options := elem.SelectOptions()
+		if options[0].Attributes.Bool("selected") || !options[1].Attributes.Bool("selected") {
+			t.Error("expected the checked option to be selected")
+		}
+
+		elem.SetValue("xl")
+		options = elem.SelectOptions()
+		if options[0].Attributes.Bool("selected") || options[1].Attributes.Bool("selected") {
+			t.Error("expected only the submitted value to be selected")
+		}
+		if !options[2].Options[0].Attributes.Bool("selected") {
+			t.Error("expected xl to be selected")
+		}
+	})
+
+	t.Run("placeholder fails required", func(t *testing.T) {
+		elem := Select("country").
+			SetAttributes(Attr("required", true)).
+			SetPlaceholder("Choose").
+			SetOptions(Option("France", "fr"))
+
+		if elem.IsValid() {
+			t.Error("expected the placeholder not to satisfy required")
+		}
+	})
+
+	t.Run("placeholder attribute on other elements", func(t *testing.T) {
+		elem := Text("name").SetPlaceholder("Jane Doe")
+
+		if elem.Attributes().String("placeholder") != "Jane Doe" || elem.Placeholder() != "Jane Doe" {
+			t.Errorf("expected placeholder attribute, got %v", elem.Attributes())
+		}
+	})
+}
+
+func TestElement_Values(t *testing.T) {
+	elem := Select("tags").
+		SetAttributes(Attr("multiple", true)).
+		SetOptions(Option("Go", "go"), Option("Rust", "rust"), Option("Zig", "zig"))
+
+	if elem.Values() != nil {
+		t.Errorf("expected no values, got %v", elem.Values())
+	}
+
+	elem.SetValues("go", "zig")
+	if !slices.Equal(elem.Values(), []string{"go", "zig"}) || elem.Value() != "go" {
+		t.Errorf("expected go and zig, got %v", elem.Values())
+	}
+
+	options := elem.SelectOptions()
+	if !options[0].Attributes.Bool("selected") || options[1].Attributes.Bool("selected") || !options[2].Attributes.Bool("selected") {
+		t.Error("expected go and zig to be selected")
+	}
+
+	elem.SetValue("rust")
+	if !slices.Equal(elem.Values(), []string{"rust"}) {
+		t.Errorf("expected SetValue to replace the values, got %v", elem.Values())
+	}
+}
+
+func TestOption_SetAttributes(t *testing.T) {
+	base := Option("France", "fr").SetAttributes(Attr("data-code", "33"))
+	other := base.SetAttributes(Attr("data-code", "34"))
+
+	if base.Attributes.String("data-code") != "33" {
+		t.Errorf("expected options not to share attributes, got %s", base.Attributes.String("data-code"))
+	}
+	if other.Attributes.String("data-code") != "34" {
+		t.Errorf("expected data-code=34, got %s", other.Attributes.String("data-code"))
+	}
+
+	radio := Radio("country").SetAttributes(Id("country")).SetOptions(other)
+	if radio.RadioOptions()[0].Attributes.String("data-code") != "34" {
+		t.Error("expected radio options to render the option attributes")
+	}
+}
+
 func TestElement_RenderError(t *testing.T) {
 	elem := Text("test").SetAttributes(Id("test-field"))
 	elem = elem.SetError("This field is required")
@@ -383,7 +552,7 @@ func TestElement_RenderError(t *testing.T) {
 	result := elem.Render()
 	htmlStr := cleanHTML(result)
 
-	expected := `<div><div><input aria-errormessage="test-field-error" id="test-field" name="test" type="text"><span id="test-field-error">This field is required</span></div></div>`
+	expected := `<div><div><input aria-describedby="test-field-error" aria-errormessage="test-field-error" id="test-field" name="test" type="text"><span id="test-field-error">This field is required</span></div></div>`
 
 	if htmlStr != expected {
 		t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, htmlStr)
@@ -436,6 +605,65 @@ func TestElement_RenderRadio(t *testing.T) {
 	}
 }
 
+func TestElement_RenderRadioGroup(t *testing.T) {
+	t.Run("renders a fieldset with the label as legend", func(t *testing.T) {
+		elem := Radio("plan").
+			SetLabel("Plan").
+			SetAttributes(Id("plan"), Attr("required", true), Attr("data-track", "plan")).
+			SetOptions(
+				Option("Free", "free").SetChecked(true),
+				Option("Pro", "pro").SetHint("Billed monthly"),
+				Option("Enterprise", "enterprise").SetDisabled(true),
+			)
+
+		expected := `<fieldset><legend>Plan *</legend>` +
+			`<label for="plan-0"><input checked data-track="plan" id="plan-0" name="plan" required type="radio" value="free"><span>Free</span></label>` +
+			`<label for="plan-1"><input aria-describedby="plan-1-hint" data-track="plan" id="plan-1" name="plan" required type="radio" value="pro"><span>Pro</span></label>` +
+			`<i id="plan-1-hint">Billed monthly</i>` +
+			`<label for="plan-2"><input data-track="plan" disabled id="plan-2" name="plan" required type="radio" value="enterprise"><span>Enterprise</span></label>` +
+			`</fieldset>`
+
+		if result := cleanHTML(elem.Render()); result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual: %s", expected, result)
+		}
+	})
+
+	t.Run("the value takes precedence over the checked options", func(t *testing.T) {
+		elem := Radio("plan").SetOptions(
+			Option("Free", "free").SetChecked(true),
+			Option("Pro", "pro"),
+		)
+		elem.SetValue("pro")
+
+		options := elem.RadioOptions()
+		if options[0].Attributes.Bool("checked") {
+			t.Error("expected free not to be checked")
+		}
+		if !options[1].Attributes.Bool("checked") {
+			t.Error("expected pro to be checked")
+		}
+	})
+
+	t.Run("shares the descriptions and invalid state", func(t *testing.T) {
+		elem := Radio("plan").
+			SetAttributes(Id("plan")).
+			SetHint("Choose one").
+			SetError("Required").
+			SetOptions(Option("Free", "free").SetHint("No card needed"))
+
+		option := elem.RadioOptions()[0]
+		if v := option.Attributes.String(AriaDescribedByAttribute); v != "plan-hint plan-error plan-0-hint" {
+			t.Errorf("expected combined descriptions, got %s", v)
+		}
+		if !option.IsInvalid() {
+			t.Error("expected the option to be invalid")
+		}
+		if elem.Attributes().String("value") != "" {
+			t.Error("expected the element attributes to be left untouched")
+		}
+	})
+}
+
 func TestElement_RenderWithBooleanAttributes(t *testing.T) {
 	elem := Text("test").SetAttributes(
 		Id("test-bool"),
@@ -518,3 +746,51 @@ func TestElement_MultipleFiles(t *testing.T) {
 		}
 	})
 }
+
+func TestElement_SetTemplate(t *testing.T) {
+	r, err := newTemplateRenderer(&templatesOverrideOptions{
+		filesystem: fstest.MapFS{
+			"toggle-switch.tmpl": &fstest.MapFile{Data: []byte(`<div class="switch">{{ template "checkbox.control" . }}</div>`)},
+		},
+		patterns: []string{"*.tmpl"},
+	}, ThemeDefault)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("renders the selected template", func(t *testing.T) {
+		elem := Checkbox("newsletter").
+			SetAttributes(Id("newsletter")).
+			SetTemplate(" toggle-switch.tmpl ")
+		elem.setRenderer(r)
+
+		result, err := elem.RenderE()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := `<div class="switch"><input id="newsletter" name="newsletter" type="checkbox"></div>`
+		if cleanHTML(result) != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, cleanHTML(result))
+		}
+	})
+
+	t.Run("leaves other elements untouched", func(t *testing.T) {
+		elem := Checkbox("terms").SetAttributes(Id("terms"))
+		elem.setRenderer(r)
+
+		expected := `<div><label for="terms"><input id="terms" name="terms" type="checkbox"></label></div>`
+		if result := cleanHTML(elem.Render()); result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, result)
+		}
+	})
+
+	t.Run("returns an error for a missing template", func(t *testing.T) {
+		elem := Checkbox("missing").SetTemplate("missing.tmpl")
+		elem.setRenderer(r)
+
+		if _, err := elem.RenderE(); !errors.Is(err, ErrTemplateNotFound) {
+			t.Errorf("expected ErrTemplateNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package goform
+
+import (
+	"html/template"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// eventHandlers lists the event handler attributes of HTML elements
+var eventHandlers = []string{
+	"onabort",
+	"onanimationcancel",
+	"onanimationend",
+	"onanimationiteration",
+	"onanimationstart",
+	"onauxclick",
+	"onbeforeinput",
+	"onbeforematch",
+	"onbeforetoggle",
+	"onblur",
+	"oncancel",
+	"oncanplay",
+	"oncanplaythrough",
+	"onchange",
+	"onclick",
+	"onclose",
+	"oncontextlost",
+	"oncontextmenu",
+	"oncontextrestored",
+	"oncopy",
+	"oncuechange",
+	"oncut",
+	"ondblclick",
+	"ondrag",
+	"ondragend",
+	"ondragenter",
+	"ondragleave",
+	"ondragover",
+	"ondragstart",
+	"ondrop",
+	"ondurationchange",
+	"onemptied",
+	"onended",
+	"onerror",
+	"onfocus",
+	"onfocusin",
+	"onfocusout",
+	"onformdata",
+	"ongotpointercapture",
+	"oninput",
+	"oninvalid",
+	"onkeydown",
+	"onkeypress",
+	"onkeyup",
+	"onload",
+	"onloadeddata",
+	"onloadedmetadata",
+	"onloadstart",
+	"onlostpointercapture",
+	"onmousedown",
+	"onmouseenter",
+	"onmouseleave",
+	"onmousemove",
+	"onmouseout",
+	"onmouseover",
+	"onmouseup",
+	"onpaste",
+	"onpause",
+	"onplay",
+	"onplaying",
+	"onpointercancel",
+	"onpointerdown",
+	"onpointerenter",
+	"onpointerleave",
+	"onpointermove",
+	"onpointerout",
+	"onpointerover",
+	"onpointerup",
+	"onprogress",
+	"onratechange",
+	"onreset",
+	"onresize",
+	"onscroll",
+	"onscrollend",
+	"onsearch",
+	"onsecuritypolicyviolation",
+	"onseeked",
+	"onseeking",
+	"onselect",
+	"onselectionchange",
+	"onselectstart",
+	"onslotchange",
+	"onstalled",
+	"onsubmit",
+	"onsuspend",
+	"ontimeupdate",
+	"ontoggle",
+	"ontouchcancel",
+	"ontouchend",
+	"ontouchmove",
+	"ontouchstart",
+	"ontransitioncancel",
+	"ontransitionend",
+	"ontransitionrun",
+	"ontransitionstart",
+	"onvolumechange",
+	"onwaiting",
+	"onwheel",
+}
+
+func isEventHandler(name string) bool {
+	return slices.Contains(eventHandlers, name)
+}
+
+// WithNonce sets the nonce of the script binding the event handlers of the form,
+// it must match the script-src nonce of the Content-Security-Policy of the page
+func WithNonce(nonce string) FormOption {
+	return func(options *formOptions) {
+		options.nonce = nonce
+	}
+}
+
+// EventHandler is an event handler attribute bound by the script of the form
+type EventHandler struct {
+	Id    string
+	Event string
+	Code  template.JS
+}
+
+type attributed interface {
+	Attributes() Attrs
+}
+
+// EventHandlers returns the event handlers of the form and its children, the handlers of
+// a radio group are bound to each option and the nodes without id are left out
+func (f *form) EventHandlers() []EventHandler {
+	handlers := make([]EventHandler, 0)
+
+	collect := func(attributes Attrs) {
+		id := attributes.String("id")
+		if id == "" {
+			return
+		}
+		for _, name := range slices.Sorted(maps.Keys(attributes)) {
+			if !isEventHandler(name) {
+				continue
+			}
+			handlers = append(handlers, EventHandler{
+				Id:    id,
+				Event: strings.TrimPrefix(name, "on"),
+				// the code is trusted but must not close the script element
+				Code: template.JS(strings.ReplaceAll(attributes.String(name), "</", `<\/`)), //nolint:gosec // G203
+			})
+		}
+	}
+
+	collect(f.attributes)
+	walk(f.children, nil, func(node Renderer, _ []Renderer) {
+		// radio groups render an input per option, the group id itself is never rendered
+		if e, ok := node.(*element); ok && e.attributes.String("type") == InputTypeRadio && len(e.options) > 0 {
+			for _, option := range e.RadioOptions() {
+				collect(option.Attributes)
+			}
+			return
+		}
+		if a, ok := node.(attributed); ok {
+			collect(a.Attributes())
+		}
+	})
+
+	return handlers
+}
+
+// RenderEventHandlers renders the script binding the event handlers with addEventListener,
+// inline handlers are blocked by nonce-based Content-Security-Policies
+func (f *form) RenderEventHandlers() template.HTML {
+	return f.renderer.Render("event-handlers.tmpl", struct {
+		Nonce    string
+		Handlers []EventHandler
+	}{
+		Nonce:    f.options.nonce,
+		Handlers: f.EventHandlers(),
+	})
+}
@@ -2,6 +2,8 @@ package goform
 
 import (
 	"html/template"
+	"io"
+	"strings"
 )
 
 func FieldSet(legend string, children ...Renderer) *fieldSet {
@@ -19,6 +21,7 @@ func FieldSet(legend string, children ...Renderer) *fieldSet {
 
 type fieldSet struct {
 	legend     string
+	custom     string
 	attributes Attrs
 	renderer   TemplateRenderer
 	children   []Renderer
@@ -43,8 +46,48 @@ func (f *fieldSet) Children() []Renderer {
 	return f.children
 }
 
+func (f *fieldSet) templateName() string {
+	if f.custom != "" {
+		return f.custom
+	}
+	return "fieldset.tmpl"
+}
+
+func (f *fieldSet) SetTemplate(name string) *fieldSet {
+	f.custom = strings.TrimSpace(name)
+	return f
+}
+
+// fieldSetView is the data of the fieldset template, it exposes the fieldset along with the writer it is rendered to
+type fieldSetView struct {
+	*fieldSet
+	w io.Writer
+}
+
+// RenderChild renders a child of the fieldset straight into the writer of the fieldset
+func (v fieldSetView) RenderChild(child Renderer) (template.HTML, error) {
+	return renderChild(v.w, child)
+}
+
 func (f *fieldSet) Render() template.HTML {
-	return f.renderer.Render("fieldset.tmpl", f)
+	h, err := f.RenderE()
+	if err != nil {
+		return renderErrorHTML(err)
+	}
+	return h
+}
+
+func (f *fieldSet) RenderE() (template.HTML, error) {
+	return renderContainer(f)
+}
+
+func (f *fieldSet) RenderTo(w io.Writer) error {
+	return f.renderer.RenderTo(w, f.templateName(), fieldSetView{fieldSet: f, w: w})
+}
+
+func (f *fieldSet) setRenderer(renderer TemplateRenderer) {
+	f.renderer = renderer
+	applyRenderer(renderer, f.children...)
 }
 
 var _ Container = (*fieldSet)(nil)
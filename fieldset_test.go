@@ -4,6 +4,7 @@ import (
 	"html/template"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestFieldSet_Creation(t *testing.T) {
@@ -353,3 +354,28 @@ func TestFieldSet_NilElements(t *testing.T) {
 		}
 	})
 }
+
+func TestFieldSet_SetTemplate(t *testing.T) {
+	r, err := newTemplateRenderer(&templatesOverrideOptions{
+		filesystem: fstest.MapFS{
+			"details.tmpl": &fstest.MapFile{Data: []byte(`<details><summary>{{ .Legend }}</summary>{{ range .Children }}{{ form_component . }}{{ end }}</details>`)},
+		},
+		patterns: []string{"*.tmpl"},
+	}, ThemeDefault)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fs := FieldSet("Advanced", Hidden("token").SetAttributes(Id("token"))).SetTemplate("details.tmpl")
+	fs.setRenderer(r)
+
+	result, err := fs.RenderE()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `<details><summary>Advanced</summary><input id="token" name="token" type="hidden"></details>`
+	if cleanHTML(result) != expected {
+		t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, cleanHTML(result))
+	}
+}
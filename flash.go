@@ -0,0 +1,209 @@
+package goform
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const FlashCookieName = "form_flash"
+
+// flashSignatureName binds the signature to flash states so that other signed values cannot be replayed as a state
+const flashSignatureName = "form_flash"
+
+// maxCookieSize is the size browsers are required to support for a single cookie
+const maxCookieSize = 4096
+
+// flashMaxAge is the time a state can be restored after its submission,
+// it only has to outlive the redirection following the submission
+const flashMaxAge = 5 * time.Minute
+
+var (
+	ErrInvalidFlash  = errors.New("invalid flash state")
+	ErrFlashTooLarge = errors.New("flash state is too large")
+)
+
+// FlashStore keeps the state of a form between a failed submission and the next GET
+type FlashStore interface {
+	// Save stores the encoded state, the response can be used to persist it
+	Save(w http.ResponseWriter, r *http.Request, state string) error
+	// Load returns the stored state and deletes it, an empty string when there is none
+	Load(w http.ResponseWriter, r *http.Request) (string, error)
+}
+
+type flashOptions struct {
+	store   FlashStore
+	keyring *Keyring
+}
+
+// WithFlash persists the values and errors of the form in SaveFlash and restores them in RestoreFlash,
+// states are signed with the keyring and the values of password and file elements are never stored
+func WithFlash(store FlashStore, keyring *Keyring) FormOption {
+	return func(options *formOptions) {
+		options.flash = &flashOptions{
+			store:   store,
+			keyring: keyring,
+		}
+	}
+}
+
+// CookieFlashStore keeps the state in a cookie that is deleted as soon as it is read
+type CookieFlashStore struct {
+	CookieName string
+	Path       string
+	Domain     string
+	Secure     bool
+	SameSite   http.SameSite
+}
+
+func (s *CookieFlashStore) cookie(value string) *http.Cookie {
+	name := s.CookieName
+	if name == "" {
+		name = FlashCookieName
+	}
+	path := s.Path
+	if path == "" {
+		path = "/"
+	}
+	sameSite := s.SameSite
+	if sameSite == 0 {
+		sameSite = http.SameSiteLaxMode
+	}
+
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   s.Domain,
+		Secure:   s.Secure,
+		MaxAge:   int(flashMaxAge.Seconds()),
+		HttpOnly: true,
+		SameSite: sameSite,
+	}
+}
+
+func (s *CookieFlashStore) Save(w http.ResponseWriter, _ *http.Request, state string) error {
+	cookie := s.cookie(state)
+	if len(cookie.String()) > maxCookieSize {
+		return ErrFlashTooLarge
+	}
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+func (s *CookieFlashStore) Load(w http.ResponseWriter, r *http.Request) (string, error) {
+	expired := s.cookie("")
+	cookie, err := r.Cookie(expired.Name)
+	if err != nil {
+		return "", nil
+	}
+
+	expired.MaxAge = -1
+	http.SetCookie(w, expired)
+
+	return cookie.Value, nil
+}
+
+// encodeState serializes the state as the time it was issued at and base64 JSON followed by their signature,
+// the signature is bound to the form so that a state cannot be restored into another form
+func encodeState(state formState, keyring *Keyring, formKey string) (string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	value := strconv.FormatInt(now().Unix(), 10) + "." + base64.RawURLEncoding.EncodeToString(data)
+	return value + "." + keyring.SignContext(formKey, flashSignatureName, value), nil
+}
+
+func decodeState(encoded string, keyring *Keyring, formKey string) (formState, error) {
+	var state formState
+
+	i := strings.LastIndex(encoded, ".")
+	if i < 0 || !keyring.VerifyContext(formKey, flashSignatureName, encoded[:i], encoded[i+1:]) {
+		return state, ErrInvalidFlash
+	}
+
+	issued, payload, ok := strings.Cut(encoded[:i], ".")
+	if !ok {
+		return state, ErrInvalidFlash
+	}
+	seconds, err := strconv.ParseInt(issued, 10, 64)
+	if err != nil {
+		return state, fmt.Errorf("%w: %w", ErrInvalidFlash, err)
+	}
+	if now().Sub(time.Unix(seconds, 0)) > flashMaxAge {
+		return state, fmt.Errorf("%w: state has expired", ErrInvalidFlash)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return state, fmt.Errorf("%w: %w", ErrInvalidFlash, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("%w: %w", ErrInvalidFlash, err)
+	}
+
+	return state, nil
+}
+
+// verifyFlashKey requires an explicit form ID, it identifies the form in the signature of its states
+// so that forms with the same elements, such as the delete forms of a list, cannot restore each other's state
+func (f *form) verifyFlashKey() error {
+	if !f.hasExplicitId() {
+		return fmt.Errorf("%w: flash states are bound to the form id", ErrMissingFormId)
+	}
+	return nil
+}
+
+// SaveFlash stores the state of the form, it reports false when the form has no flash store.
+// The form must have an explicit ID, ErrMissingFormId is returned otherwise.
+func (f *form) SaveFlash(w http.ResponseWriter, r *http.Request) (bool, error) {
+	if f.options.flash == nil {
+		return false, nil
+	}
+	if err := f.verifyFlashKey(); err != nil {
+		return false, err
+	}
+
+	state, err := encodeState(f.captureState(false), f.options.flash.keyring, f.Id())
+	if err != nil {
+		return false, fmt.Errorf("failed to encode flash state: %w", err)
+	}
+	if err := f.options.flash.store.Save(w, r, state); err != nil {
+		return false, fmt.Errorf("failed to save flash state: %w", err)
+	}
+
+	return true, nil
+}
+
+// RestoreFlash applies the state saved by the previous request, if any.
+// A tampered or expired state, or the state of another form, returns ErrInvalidFlash and leaves the form untouched.
+func (f *form) RestoreFlash(w http.ResponseWriter, r *http.Request) error {
+	if f.options.flash == nil {
+		return nil
+	}
+	if err := f.verifyFlashKey(); err != nil {
+		return err
+	}
+
+	encoded, err := f.options.flash.store.Load(w, r)
+	if err != nil {
+		return fmt.Errorf("failed to load flash state: %w", err)
+	}
+	if encoded == "" {
+		return nil
+	}
+
+	state, err := decodeState(encoded, f.options.flash.keyring, f.Id())
+	if err != nil {
+		return err
+	}
+
+	f.restoreState(state, false)
+	return nil
+}
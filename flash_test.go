@@ -0,0 +1,201 @@
+package goform
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForm_Flash(t *testing.T) {
+	keyring := NewKeyring([]byte("current"))
+	store := &CookieFlashStore{}
+
+	newForm := func() *form {
+		return Form(WithFlash(store, keyring)).SetAttributes(Id("profile")).AddChildren(
+			Email("email").SetAttributes(Attr("required", true)),
+			Password("password"),
+			Select("colors").SetAttributes(Attr("multiple", true)).SetOptions(
+				Option("Red", "red"),
+				Option("Blue", "blue"),
+			),
+			Submit("save"),
+		)
+	}
+
+	f := newForm()
+	if err := f.PopulateFromValues(url.Values{
+		"email":    {""},
+		"password": {"secret"},
+		"colors":   {"red", "blue"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.Elements()["email"].(*element).SetError("Enter your email")
+	f.SetError("Please fix the errors")
+
+	rec := httptest.NewRecorder()
+	if saved, err := f.SaveFlash(rec, httptest.NewRequest(http.MethodPost, "/", nil)); !saved || err != nil {
+		t.Fatalf("expected the state to be saved, got %v %v", saved, err)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != FlashCookieName || !cookies[0].HttpOnly {
+		t.Fatalf("expected a http only cookie, got %v", cookies)
+	}
+	if strings.Contains(cookies[0].Value, "secret") {
+		t.Error("expected the password to be left out")
+	}
+
+	t.Run("restores the state into a fresh form", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+
+		rec := httptest.NewRecorder()
+		restored := newForm()
+		if err := restored.RestoreFlash(rec, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		elements := restored.Elements()
+		email := elements["email"].(*element)
+		if email.Error() != "Enter your email" || !email.IsInvalid() {
+			t.Errorf("expected the email error to be restored, got %q", email.Error())
+		}
+		if colors := elements["colors"].(*element).Values(); len(colors) != 2 || colors[1] != "blue" {
+			t.Errorf("expected the selected colors to be restored, got %v", colors)
+		}
+		if password := elements["password"].Value(); password != "" {
+			t.Errorf("expected the password to be empty, got %q", password)
+		}
+		if restored.Error() != "Please fix the errors" {
+			t.Errorf("expected the form error to be restored, got %q", restored.Error())
+		}
+
+		deleted := rec.Result().Cookies()
+		if len(deleted) != 1 || deleted[0].MaxAge != -1 {
+			t.Errorf("expected the cookie to be deleted, got %v", deleted)
+		}
+	})
+
+	t.Run("ignores requests without state", func(t *testing.T) {
+		restored := newForm()
+		if err := restored.RestoreFlash(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects a tampered state", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: FlashCookieName, Value: "e30." + keyring.Sign("other", "e30")})
+
+		restored := newForm()
+		if err := restored.RestoreFlash(httptest.NewRecorder(), req); !errors.Is(err, ErrInvalidFlash) {
+			t.Errorf("expected ErrInvalidFlash, got %v", err)
+		}
+	})
+
+	t.Run("rejects states signed with another key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+
+		restored := Form(WithFlash(store, NewKeyring([]byte("other")))).SetAttributes(Id("profile")).AddChildren(Email("email"))
+		if err := restored.RestoreFlash(httptest.NewRecorder(), req); !errors.Is(err, ErrInvalidFlash) {
+			t.Errorf("expected ErrInvalidFlash, got %v", err)
+		}
+	})
+
+	t.Run("rejects the state of another form with the same elements", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+
+		restored := newForm().SetAttributes(Id("settings"))
+		if err := restored.RestoreFlash(httptest.NewRecorder(), req); !errors.Is(err, ErrInvalidFlash) {
+			t.Errorf("expected ErrInvalidFlash, got %v", err)
+		}
+		if restored.Error() != "" {
+			t.Errorf("expected the form to be untouched, got %q", restored.Error())
+		}
+	})
+
+	t.Run("requires an explicit form id", func(t *testing.T) {
+		anonymous := Form(WithFlash(store, keyring)).AddChildren(Email("email"))
+
+		if saved, err := anonymous.SaveFlash(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil)); saved || !errors.Is(err, ErrMissingFormId) {
+			t.Errorf("expected ErrMissingFormId, got %v %v", saved, err)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+		if err := anonymous.RestoreFlash(httptest.NewRecorder(), req); !errors.Is(err, ErrMissingFormId) {
+			t.Errorf("expected ErrMissingFormId, got %v", err)
+		}
+	})
+
+	t.Run("rejects an expired state", func(t *testing.T) {
+		now = func() time.Time { return time.Now().Add(flashMaxAge + time.Second) }
+		defer func() { now = time.Now }()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+
+		if err := newForm().RestoreFlash(httptest.NewRecorder(), req); !errors.Is(err, ErrInvalidFlash) {
+			t.Errorf("expected ErrInvalidFlash, got %v", err)
+		}
+	})
+
+	t.Run("rejects states larger than a cookie", func(t *testing.T) {
+		large := newForm()
+		large.SetError(strings.Repeat("x", maxCookieSize))
+
+		if saved, err := large.SaveFlash(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil)); saved || !errors.Is(err, ErrFlashTooLarge) {
+			t.Errorf("expected ErrFlashTooLarge, got %v %v", saved, err)
+		}
+	})
+
+	t.Run("does nothing without a store", func(t *testing.T) {
+		if saved, err := Form().SaveFlash(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil)); saved || err != nil {
+			t.Errorf("expected nothing to be saved, got %v %v", saved, err)
+		}
+	})
+}
+
+func TestHandler_Flash(t *testing.T) {
+	page := template.Must(template.New("page").Funcs(template.FuncMap{
+		"form": FormRenderer(),
+	}).Parse(`{{ form .Form }}`))
+
+	h := Handler(func(_ *http.Request) HandledForm {
+		return Form(WithFlash(&CookieFlashStore{}, NewKeyring([]byte("key")))).
+			SetAttributes(Id("signup")).
+			AddChildren(Text("name"))
+	}, func(_ context.Context, _ *signup) error {
+		return &SubmissionError{Message: "This name is taken"}
+	}, page, WithRedirect("/welcome"))
+
+	req := postForm(url.Values{"name": {"Jane"}})
+	req.URL.Path = "/signup"
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/signup" {
+		t.Fatalf("expected a redirection to the form, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/signup", nil)
+	for _, cookie := range rec.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, `value="Jane"`) || !strings.Contains(body, "This name is taken") {
+		t.Errorf("expected the restored form, got %d %s", rec.Code, body)
+	}
+}
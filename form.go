@@ -1,28 +1,70 @@
 package goform
 
 import (
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"reflect"
+	"slices"
 	"strings"
 )
 
 const (
 	MultipartData  = "multipart/form-data"
 	URLEncodedData = "application/x-www-form-urlencoded"
+	JSONData       = "application/json"
+)
+
+const MethodOverrideFieldName = "_method"
+
+var (
+	ErrInvalidBindTarget = errors.New("bind target must be a pointer to a struct")
+	ErrMissingFormId     = errors.New("form id is missing")
 )
 
 type Container interface {
 	Children() []Renderer
 }
 
+type signedValuer interface {
+	IsSigned() bool
+	SignatureName() string
+	setSignedValue(value, signature string)
+}
+
+type actionInput interface {
+	isAction() bool
+}
+
+type multiValuer interface {
+	IsMultiValued() bool
+	SetValues(values ...string)
+	Values() []string
+}
+
 // FormOption defines a functional option for configuring forms
 type FormOption func(*formOptions)
 
 // formOptions holds configuration options for the form
 type formOptions struct {
-	maxMemory int64 // Maximum memory for multipart form parsing (in bytes)
+	maxMemory         int64           // Maximum memory for multipart form parsing (in bytes)
+	theme             Theme           // Template set used to render the form and its children
+	errorSummaryTitle string          // Heading of the error summary block
+	idGenerator       IdGenerator     // Generates the IDs of the form elements
+	csrf              CSRFTokenStore  // Issues and verifies the CSRF tokens
+	spam              *spamProtection // Honeypot and minimum fill time
+	populateLocked    bool            // Populate disabled and readonly elements
+	strict            bool            // Reject fields that match no element
+	maxFields         int             // Maximum number of fields of a request
+	maxValuesPerField int             // Maximum number of values of a field
+	maxBodySize       int64           // Maximum size of the request body (in bytes)
+	flash             *flashOptions   // Persists the state of the form between requests
+	sensitiveState    bool            // Include password and file values in marshaled states
+	nonce             string          // Nonce of the script binding the event handlers
 }
 
 // WithMaxMemory sets the maximum memory for multipart form parsing
@@ -32,34 +74,84 @@ func WithMaxMemory(maxMemory int64) FormOption {
 	}
 }
 
+// WithTheme renders the form and its children with one of the built-in themes
+func WithTheme(theme Theme) FormOption {
+	return func(options *formOptions) {
+		options.theme = theme
+	}
+}
+
+// WithErrorSummaryTitle sets the heading of the error summary block
+func WithErrorSummaryTitle(title string) FormOption {
+	return func(options *formOptions) {
+		options.errorSummaryTitle = strings.TrimSpace(title)
+	}
+}
+
+// WithLockedFieldsPopulation lets population overwrite disabled and readonly elements,
+// which keep their server-side value by default
+func WithLockedFieldsPopulation() FormOption {
+	return func(options *formOptions) {
+		options.populateLocked = true
+	}
+}
+
 type form struct {
+	autoId     string
 	error      string
+	custom     string
 	children   []Renderer
 	renderer   TemplateRenderer
 	attributes Attrs
 	options    formOptions
+	csrf       *element
+	honeypot   *element
+	timestamp  *element
+	override   *element
+	spam       error
 }
 
 func Form(modifiers ...FormOption) *form {
 	options := formOptions{
-		maxMemory: 32 << 20, // 32 MB default
+		maxMemory:         32 << 20, // 32 MB default
+		theme:             defaultTheme,
+		errorSummaryTitle: "There is a problem",
 	}
 
 	for _, option := range modifiers {
 		option(&options)
 	}
 
+	id := GenId()
 	f := &form{
+		autoId:   id,
 		options:  options,
 		children: make([]Renderer, 0),
-		renderer: getTemplateRenderer(),
+		renderer: getThemeRenderer(options.theme),
 		attributes: Attributes(
-			Attr("id", GenId()),
+			Attr("id", id),
 			Attr("method", http.MethodPost),
 			Attr("enctype", URLEncodedData),
 		),
 	}
 
+	if options.csrf != nil {
+		f.csrf = Hidden(CSRFFieldName).SetAttributes(Id(""))
+		applyRenderer(f.renderer, f.csrf)
+	}
+
+	if options.spam != nil {
+		f.honeypot = Text(HoneypotFieldName).
+			SetLabel("Leave this field empty").
+			SetAttributes(Attr("tabindex", -1), Attr("autocomplete", "off")).
+			SetTemplate("honeypot.tmpl")
+		f.timestamp = Hidden(TimestampFieldName).SetAttributes(Id(""))
+		f.timestamp.SetValue(options.spam.timestamp())
+		applyRenderer(f.renderer, f.honeypot)
+		applyRenderer(f.renderer, f.timestamp)
+		f.syncHoneypotId()
+	}
+
 	return f
 }
 
@@ -67,6 +159,12 @@ func (f *form) Id() string {
 	return f.attributes.String("id")
 }
 
+// hasExplicitId reports whether the form ID was set with SetAttributes rather than generated,
+// generated IDs change on every request and cannot identify the form across requests
+func (f *form) hasExplicitId() bool {
+	return f.Id() != "" && f.Id() != f.autoId
+}
+
 func (f *form) SetError(value string) *form {
 	f.error = strings.TrimSpace(value)
 	if f.error == "" {
@@ -82,12 +180,46 @@ func (f *form) Error() string {
 }
 
 func (f *form) SetAttributes(modifiers ...attrModifier) *form {
+	id := f.Id()
+	method := f.attributes.String("method")
 	for _, mod := range modifiers {
 		mod(f.attributes)
 	}
+	if f.Id() != id {
+		f.assignIds()
+	}
+	if f.attributes.String("method") != method {
+		f.syncMethod()
+	}
 	return f
 }
 
+// syncMethod renders the methods HTML forms do not support as a POST with a method override field
+func (f *form) syncMethod() {
+	method := strings.ToUpper(f.attributes.String("method"))
+
+	switch method {
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+		f.attributes.Set("method", http.MethodPost)
+		f.override = Hidden(MethodOverrideFieldName).SetAttributes(Id(""))
+		f.override.SetValue(method)
+		applyRenderer(f.renderer, f.override)
+	default:
+		f.override = nil
+	}
+}
+
+// Method returns the HTTP method the form is submitted with, including overridden methods
+func (f *form) Method() string {
+	if f.override != nil {
+		return f.override.Value()
+	}
+	if method := strings.ToUpper(f.attributes.String("method")); method != "" {
+		return method
+	}
+	return http.MethodGet
+}
+
 func (f *form) Attributes() Attrs {
 	return f.attributes
 }
@@ -95,9 +227,11 @@ func (f *form) Attributes() Attrs {
 func (f *form) AddChildren(children ...Renderer) *form {
 	for _, c := range children {
 		if c != nil {
+			applyRenderer(f.renderer, c)
 			f.children = append(f.children, c)
 		}
 	}
+	f.assignIds()
 	return f
 }
 
@@ -105,8 +239,65 @@ func (f *form) Children() []Renderer {
 	return f.children
 }
 
+// SecurityFields returns the fields injected by the form options, they are rendered before the children
+func (f *form) SecurityFields() []Renderer {
+	fields := make([]Renderer, 0)
+	if f.override != nil {
+		fields = append(fields, f.override)
+	}
+	if f.csrf != nil {
+		fields = append(fields, f.csrf)
+	}
+	if f.honeypot != nil {
+		fields = append(fields, f.honeypot, f.timestamp)
+	}
+	return fields
+}
+
+func (f *form) templateName() string {
+	if f.custom != "" {
+		return f.custom
+	}
+	return "form.tmpl"
+}
+
+func (f *form) SetTemplate(name string) *form {
+	f.custom = strings.TrimSpace(name)
+	return f
+}
+
+// formView is the data of the form template, it exposes the form along with the writer it is rendered to
+type formView struct {
+	*form
+	w io.Writer
+}
+
+// RenderChild renders a child of the form straight into the writer of the form,
+// templates may use form_component instead at the cost of a copy
+func (v formView) RenderChild(child Renderer) (template.HTML, error) {
+	return renderChild(v.w, child)
+}
+
 func (f *form) Render() template.HTML {
-	return f.renderer.Render("form.tmpl", f)
+	h, err := f.RenderE()
+	if err != nil {
+		return renderErrorHTML(err)
+	}
+	return h
+}
+
+func (f *form) RenderE() (template.HTML, error) {
+	return renderContainer(f)
+}
+
+func (f *form) RenderTo(w io.Writer) error {
+	if err := f.verifyCSRFIssued(); err != nil {
+		return err
+	}
+	if err := f.verifyFormId(); err != nil {
+		return err
+	}
+	return f.renderer.RenderTo(w, f.templateName(), formView{form: f, w: w})
 }
 
 func (f *form) RenderError() template.HTML {
@@ -119,6 +310,51 @@ func (f *form) RenderError() template.HTML {
 	})
 }
 
+type ErrorSummaryItem struct {
+	Id    string
+	Label string
+	Error string
+}
+
+func (f *form) ErrorSummary() []ErrorSummaryItem {
+	items := make([]ErrorSummaryItem, 0)
+
+	walk(f.children, nil, func(node Renderer, _ []Renderer) {
+		e, ok := node.(*element)
+		// only the validation results are listed, a pristine form has no errors
+		if !ok || !e.IsInvalid() {
+			return
+		}
+
+		message := e.Error()
+		if message == "" {
+			message = "Invalid value"
+		}
+
+		items = append(items, ErrorSummaryItem{
+			Id:    e.focusId(),
+			Label: e.accessibleName(),
+			Error: message,
+		})
+	})
+
+	return items
+}
+
+func (f *form) RenderErrorSummary() template.HTML {
+	return f.renderer.Render("error-summary.tmpl", struct {
+		Id    string
+		Title string
+		Error string
+		Items []ErrorSummaryItem
+	}{
+		Id:    f.Id(),
+		Title: f.options.errorSummaryTitle,
+		Error: f.error,
+		Items: f.ErrorSummary(),
+	})
+}
+
 func (f *form) PopulateFromStruct(obj any) *form {
 	t := reflect.TypeOf(obj)
 	v := reflect.ValueOf(obj)
@@ -146,60 +382,100 @@ func (f *form) PopulateFromStruct(obj any) *form {
 }
 
 func (f *form) PopulateFromRequest(r *http.Request) error {
-	if err := r.ParseForm(); err != nil {
-		return fmt.Errorf("failed to parse form data: %w", err)
+	values, err := f.parseRequest(r)
+	if err != nil {
+		return err
 	}
 
-	// Also parse multipart form if present (for file uploads)
-	if r.MultipartForm == nil {
-		if err := r.ParseMultipartForm(f.options.maxMemory); err != nil {
-			// Only return error if this is actually a multipart request
-			// Non-multipart requests will fail here, which is expected
-			if strings.Contains(r.Header.Get("Content-Type"), "multipart/") {
-				return fmt.Errorf("failed to parse multipart form data: %w", err)
+	if err := f.verifyCSRFToken(r, values); err != nil {
+		return err
+	}
+
+	if err := f.verifySpam(r, values); err != nil {
+		return err
+	}
+
+	var files map[string][]*multipart.FileHeader
+	if r.MultipartForm != nil && !f.isGetForm() {
+		files = r.MultipartForm.File
+	}
+
+	if err := f.verifyFields(values, files); err != nil {
+		f.SetError(err.Error())
+		return err
+	}
+
+	f.populateValues(values)
+
+	elements := f.bindableElements()
+	for name, files := range files {
+		element, ok := elements[name]
+		if !ok {
+			continue
+		}
+
+		var filenames []string
+		for _, file := range files {
+			if file.Filename != "" {
+				filenames = append(filenames, file.Filename)
 			}
 		}
+		element.SetValue(strings.Join(filenames, ", "))
 	}
 
-	elements := f.Elements()
+	f.markInvalidElements()
 
-	for name, values := range r.Form {
+	return nil
+}
+
+// PopulateFromValues populates the form from values that do not come from an HTTP request, such as in tests
+func (f *form) PopulateFromValues(values url.Values) error {
+	if err := f.verifyFields(values, nil); err != nil {
+		f.SetError(err.Error())
+		return err
+	}
+
+	f.populateValues(values)
+	f.markInvalidElements()
+
+	return nil
+}
+
+func (f *form) populateValues(values url.Values) {
+	elements := f.bindableElements()
+
+	for name, submitted := range values {
 		element, ok := elements[name]
 		if !ok {
 			continue
 		}
 
-		// For elements that can have multiple values (like checkboxes with same name),
-		// we'll take the first value for now. This could be extended later.
-		if len(values) > 0 {
-			element.SetValue(values[0])
+		if m, ok := element.(multiValuer); ok && m.IsMultiValued() {
+			m.SetValues(submitted...)
+			continue
 		}
-	}
 
-	if r.MultipartForm != nil {
-		for name, files := range r.MultipartForm.File {
-			element, ok := elements[name]
-			if !ok {
-				continue
+		if s, ok := element.(signedValuer); ok && s.IsSigned() {
+			if len(submitted) > 0 {
+				s.setSignedValue(submitted[0], values.Get(s.SignatureName()))
 			}
+			continue
+		}
 
-			var filenames []string
-			for _, file := range files {
-				if file.Filename != "" {
-					filenames = append(filenames, file.Filename)
-				}
-			}
-			element.SetValue(strings.Join(filenames, ", "))
+		// For elements that can have multiple values (like checkboxes with same name),
+		// we'll take the first value for now. This could be extended later.
+		if len(submitted) > 0 {
+			element.SetValue(submitted[0])
 		}
 	}
+}
 
-	for _, element := range elements {
+func (f *form) markInvalidElements() {
+	for _, element := range f.Elements() {
 		if !element.IsValid() {
 			element.MarkAsInvalid()
 		}
 	}
-
-	return nil
 }
 
 func (f *form) IsValid() (bool, map[string]string) {
@@ -214,31 +490,96 @@ func (f *form) IsValid() (bool, map[string]string) {
 		}
 	}
 
+	// spam is reported by SpamError rather than as a field error
+	if f.spam != nil {
+		isValid = false
+	}
+
 	return isValid, errors
 }
 
+// ValidationErrors returns the validation error codes of the invalid elements, keyed by element name
+func (f *form) ValidationErrors() map[string]string {
+	errors := make(map[string]string)
+
+	walk(f.children, nil, func(node Renderer, _ []Renderer) {
+		if e, ok := node.(*element); ok {
+			if code := e.ValidationError(); code != "" {
+				errors[e.Name()] = code
+			}
+		}
+	})
+
+	return errors
+}
+
 func (f *form) Elements() map[string]Element {
 	elements := make(map[string]Element)
 
-	for _, el := range f.children {
-		switch el := el.(type) {
-		case Container:
-			// @TODO we should handle nested containers
-			for _, c := range el.Children() {
-				e, ok := c.(Element)
-				if !ok {
-					continue
-				}
-				elements[e.Name()] = e
+	walk(f.children, nil, func(node Renderer, _ []Renderer) {
+		if e, ok := node.(Element); ok {
+			elements[e.Name()] = e
+		}
+	})
+
+	return elements
+}
+
+// bindableElements returns the elements that accept submitted values,
+// action inputs are never bound and locked elements keep their server-side value
+func (f *form) bindableElements() map[string]Element {
+	elements := make(map[string]Element)
+
+	walk(f.children, nil, func(node Renderer, parents []Renderer) {
+		e, ok := node.(Element)
+		if !ok {
+			return
+		}
+		if el, ok := node.(*element); ok {
+			if el.isAction() {
+				return
+			}
+			if !f.options.populateLocked && (el.isLocked() || inDisabledFieldSet(parents)) {
+				return
 			}
-		case Element:
-			elements[el.Name()] = el
 		}
-	}
+		elements[e.Name()] = e
+	})
 
 	return elements
 }
 
+func inDisabledFieldSet(parents []Renderer) bool {
+	return slices.ContainsFunc(parents, func(parent Renderer) bool {
+		fs, ok := parent.(*fieldSet)
+		return ok && fs.attributes.Bool("disabled")
+	})
+}
+
+func walk(children []Renderer, parents []Renderer, fn func(node Renderer, parents []Renderer)) {
+	for _, c := range children {
+		fn(c, parents)
+		if container, ok := c.(Container); ok {
+			walk(container.Children(), append(slices.Clone(parents), c), fn)
+		}
+	}
+}
+
+// Bind populates obj like Populate, it returns an error when obj is not a pointer to a struct
+func (f *form) Bind(obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: got %T", ErrInvalidBindTarget, obj)
+	}
+	f.Populate(obj)
+	return nil
+}
+
+// Reject sets the error rendered above the elements of the form
+func (f *form) Reject(message string) {
+	f.SetError(message)
+}
+
 func (f *form) Populate(obj any) *form {
 	v := reflect.ValueOf(obj)
 	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
@@ -267,6 +608,16 @@ func (f *form) Populate(obj any) *form {
 		if !ok {
 			continue
 		}
+		if a, ok := element.(actionInput); ok && a.isAction() {
+			continue
+		}
+
+		if m, ok := element.(multiValuer); ok && m.IsMultiValued() {
+			if fieldValue.Kind() == reflect.Slice && fieldValue.Type().Elem().Kind() == reflect.String {
+				fieldValue.Set(reflect.ValueOf(slices.Clone(m.Values())))
+				continue
+			}
+		}
 
 		formValue := element.Value()
 		if formValue == "" {
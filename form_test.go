@@ -1,13 +1,17 @@
 package goform
 
 import (
+	"errors"
+	"fmt"
 	"html/template"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"slices"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func cleanHTML(h template.HTML) string {
@@ -917,6 +921,183 @@ func TestForm_IsValid(t *testing.T) {
 	})
 }
 
+func TestForm_ValidationErrors(t *testing.T) {
+	newForm := func() *form {
+		return Form().AddChildren(
+			Text("name").SetAttributes(Attr("required", true)),
+			Select("country").SetOptions(
+				OptGroup("Europe", Option("France", "fr"), Option("Spain", "es").SetDisabled(true)),
+			),
+			Select("tags").SetAttributes(Attr("multiple", true)).SetOptions(
+				Option("Go", "go"),
+				Option("Rust", "rust"),
+			),
+			Radio("plan").SetOptions(Option("Free", "free"), Option("Pro", "pro")),
+		)
+	}
+
+	t.Run("rejects values that are not among the options", func(t *testing.T) {
+		f := newForm()
+
+		values := url.Values{
+			"name":    {"Jane"},
+			"country": {"xx"},
+			"tags":    {"go", "php"},
+			"plan":    {"gold"},
+		}
+		req := &http.Request{Method: http.MethodPost, Form: values, PostForm: values}
+		if err := f.PopulateFromRequest(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := map[string]string{
+			"country": ValidationInvalidOption,
+			"tags":    ValidationInvalidOption,
+			"plan":    ValidationInvalidOption,
+		}
+		errors := f.ValidationErrors()
+		if len(errors) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, errors)
+		}
+		for name, code := range expected {
+			if errors[name] != code {
+				t.Errorf("expected %s=%s, got %s", name, code, errors[name])
+			}
+		}
+
+		if isValid, _ := f.IsValid(); isValid {
+			t.Error("expected the form to be invalid")
+		}
+	})
+
+	t.Run("rejects disabled options", func(t *testing.T) {
+		f := newForm()
+		f.Elements()["country"].SetValue("es")
+
+		if code := f.ValidationErrors()["country"]; code != ValidationInvalidOption {
+			t.Errorf("expected %s, got %s", ValidationInvalidOption, code)
+		}
+	})
+
+	t.Run("accepts declared values", func(t *testing.T) {
+		f := newForm()
+
+		values := url.Values{
+			"name":    {"Jane"},
+			"country": {"fr"},
+			"tags":    {"go", "rust"},
+			"plan":    {"pro"},
+		}
+		req := &http.Request{Method: http.MethodPost, Form: values, PostForm: values}
+		if err := f.PopulateFromRequest(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if errors := f.ValidationErrors(); len(errors) != 0 {
+			t.Errorf("expected no errors, got %v", errors)
+		}
+
+		tags := f.Elements()["tags"].(*element)
+		if !slices.Equal(tags.Values(), []string{"go", "rust"}) {
+			t.Errorf("expected both tags, got %v", tags.Values())
+		}
+
+		var data struct {
+			Tags []string `goform:"tags"`
+		}
+		f.Populate(&data)
+		if !slices.Equal(data.Tags, []string{"go", "rust"}) {
+			t.Errorf("expected both tags, got %v", data.Tags)
+		}
+	})
+
+	t.Run("required is reported before options", func(t *testing.T) {
+		f := newForm()
+
+		if code := f.ValidationErrors()["name"]; code != ValidationRequired {
+			t.Errorf("expected %s, got %s", ValidationRequired, code)
+		}
+	})
+}
+
+func TestForm_PopulateLockedFields(t *testing.T) {
+	newForm := func(options ...FormOption) *form {
+		plan := Text("plan").SetAttributes(Attr("disabled", true))
+		plan.SetValue("free")
+		email := Email("email").SetAttributes(Attr("readonly", true))
+		email.SetValue("jane@example.com")
+		role := Text("role")
+		role.SetValue("user")
+
+		return Form(options...).AddChildren(
+			Text("name"),
+			plan,
+			email,
+			FieldSet("Admin", role).SetAttributes(Attr("disabled", true)),
+			Submit("save").SetAttributes(Attr("value", "Save")),
+		)
+	}
+
+	values := url.Values{
+		"name":  {"Jane"},
+		"plan":  {"enterprise"},
+		"email": {"mallory@example.com"},
+		"role":  {"admin"},
+		"save":  {"Pwned"},
+	}
+
+	t.Run("locked and action elements keep their value", func(t *testing.T) {
+		f := newForm()
+		if err := f.PopulateFromRequest(postForm(values)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := map[string]string{
+			"name":  "Jane",
+			"plan":  "free",
+			"email": "jane@example.com",
+			"role":  "user",
+			"save":  "Save",
+		}
+		elements := f.Elements()
+		for name, value := range expected {
+			if v := elements[name].Value(); v != value {
+				t.Errorf("expected %s=%s, got %s", name, value, v)
+			}
+		}
+	})
+
+	t.Run("locked elements can be populated with the opt-out", func(t *testing.T) {
+		f := newForm(WithLockedFieldsPopulation())
+		if err := f.PopulateFromRequest(postForm(values)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		elements := f.Elements()
+		if elements["plan"].Value() != "enterprise" || elements["email"].Value() != "mallory@example.com" || elements["role"].Value() != "admin" {
+			t.Error("expected the locked elements to be populated")
+		}
+		if elements["save"].Value() != "Save" {
+			t.Error("expected action inputs never to be bound")
+		}
+	})
+
+	t.Run("action inputs are not copied into structs", func(t *testing.T) {
+		var data struct {
+			Name string `goform:"name"`
+			Save string `goform:"save"`
+		}
+
+		f := newForm()
+		f.Elements()["name"].SetValue("Jane")
+		f.Populate(&data)
+
+		if data.Name != "Jane" || data.Save != "" {
+			t.Errorf("expected only name to be bound, got %+v", data)
+		}
+	})
+}
+
 func TestForm_Populate(t *testing.T) {
 	t.Run("populate struct from form data", func(t *testing.T) {
 		// Define a struct to populate
@@ -1218,3 +1399,257 @@ func TestForm_FunctionalOptions(t *testing.T) {
 		}
 	})
 }
+
+func benchmarkForm(fields int) *form {
+	f := Form()
+	for i := range fields / 10 {
+		children := make([]Renderer, 0, 10)
+		for j := range 10 {
+			children = append(children, Text(fmt.Sprintf("field-%d-%d", i, j)).
+				SetLabel("Field").
+				SetHint("Hint"))
+		}
+		f.AddChildren(FieldSet(fmt.Sprintf("Section %d", i), Group(children...)))
+	}
+	return f
+}
+
+func BenchmarkForm_Render(b *testing.B) {
+	f := benchmarkForm(300)
+	b.ReportAllocs()
+	for b.Loop() {
+		if f.Render() == "" {
+			b.Fatal("expected rendered form")
+		}
+	}
+}
+
+func BenchmarkForm_RenderTo(b *testing.B) {
+	f := benchmarkForm(300)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := f.RenderTo(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// bufferedForm, bufferedFieldSet and bufferedGroup render their children the way containers did before streaming,
+// every child is rendered to a string and copied into its parent
+type bufferedForm struct{ *form }
+
+type bufferedFieldSet struct{ *fieldSet }
+
+type bufferedGroup struct{ *group }
+
+func (v bufferedForm) RenderChild(child Renderer) (template.HTML, error) {
+	return renderBuffered(child)
+}
+
+func (v bufferedFieldSet) RenderChild(child Renderer) (template.HTML, error) {
+	return renderBuffered(child)
+}
+
+func (v bufferedGroup) RenderChild(child Renderer) (template.HTML, error) {
+	return renderBuffered(child)
+}
+
+func renderBuffered(child Renderer) (template.HTML, error) {
+	switch node := child.(type) {
+	case *fieldSet:
+		return node.renderer.RenderE(node.templateName(), bufferedFieldSet{node})
+	case *group:
+		return node.renderer.RenderE(node.templateName(), bufferedGroup{node})
+	}
+	return child.RenderE()
+}
+
+func TestForm_RenderToStreamsChildren(t *testing.T) {
+	f := benchmarkForm(20)
+
+	expected, err := f.renderer.RenderE(f.templateName(), bufferedForm{f})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result strings.Builder
+	if err := f.RenderTo(&result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String() != string(expected) {
+		t.Errorf("expected the streamed form to match the buffered form")
+	}
+}
+
+func BenchmarkForm_RenderBuffered(b *testing.B) {
+	f := benchmarkForm(300)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := f.renderer.RenderTo(io.Discard, f.templateName(), bufferedForm{f}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestForm_SetTemplate(t *testing.T) {
+	r, err := newTemplateRenderer(&templatesOverrideOptions{
+		filesystem: fstest.MapFS{
+			"inline.tmpl": &fstest.MapFile{Data: []byte(`<form class="inline">{{ range .Children }}{{ form_component . }}{{ end }}</form>`)},
+		},
+		patterns: []string{"*.tmpl"},
+	}, ThemeDefault)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f := Form().SetTemplate("inline.tmpl")
+	f.renderer = r
+	f.AddChildren(Submit("search").SetAttributes(Id("search")))
+
+	result, err := f.RenderE()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `<form class="inline"><input id="search" name="search" type="submit"></form>`
+	if cleanHTML(result) != expected {
+		t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, cleanHTML(result))
+	}
+}
+
+func TestForm_OverriddenTemplatesReadChildren(t *testing.T) {
+	r, err := newTemplateRenderer(&templatesOverrideOptions{
+		filesystem: fstest.MapFS{
+			"form.tmpl":     &fstest.MapFile{Data: []byte(`<form>{{ range .Children }}{{ form_component . }}{{ end }}</form>`)},
+			"fieldset.tmpl": &fstest.MapFile{Data: []byte(`<fieldset>{{ range .Children }}<p>{{ .Name }}</p>{{ end }}</fieldset>`)},
+		},
+		patterns: []string{"*.tmpl"},
+	}, ThemeDefault)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f := Form()
+	f.renderer = r
+	f.AddChildren(FieldSet("Address", Text("city"), Text("street")))
+
+	result, err := f.RenderE()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `<form><fieldset><p>city</p><p>street</p></fieldset></form>`
+	if cleanHTML(result) != expected {
+		t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, cleanHTML(result))
+	}
+}
+
+func TestForm_ElementsNested(t *testing.T) {
+	f := Form().AddChildren(
+		Text("username"),
+		FieldSet("Details", Group(Email("email"), FieldSet("Address", Text("city")))),
+	)
+
+	elements := f.Elements()
+	for _, name := range []string{"username", "email", "city"} {
+		if _, ok := elements[name]; !ok {
+			t.Errorf("expected element %s to be found", name)
+		}
+	}
+}
+
+func TestForm_ErrorSummary(t *testing.T) {
+	newForm := func() *form {
+		f := Form(WithErrorSummaryTitle("Fix the following")).
+			SetAttributes(Id("signup")).
+			AddChildren(
+				Text("username").SetLabel("Username").SetAttributes(Id("username"), Attr("required", true)),
+				FieldSet("Contact",
+					Email("email").SetAttributes(Id("email"), Attr("aria-label", "Email address")).
+						SetError("Enter a valid email"),
+					Phone("phone").SetAttributes(Id("phone")),
+				),
+				FieldSet("Plan",
+					Radio("plan").SetLabel("Plan").SetAttributes(Id("plan"), Attr("required", true)).
+						SetOptions(Option("Free", "free")),
+				),
+				Text("nickname").SetAttributes(Id("nickname")).SetError("Too short"),
+			)
+		if err := f.PopulateFromValues(url.Values{"username": {""}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return f
+	}
+
+	t.Run("lists invalid elements in document order", func(t *testing.T) {
+		items := newForm().ErrorSummary()
+
+		expected := []ErrorSummaryItem{
+			{Id: "username", Label: "Username", Error: "Invalid value"},
+			{Id: "email", Label: "Email address", Error: "Enter a valid email"},
+			{Id: "plan-0", Label: "Plan", Error: "Invalid value"},
+			{Id: "nickname", Label: "nickname", Error: "Too short"},
+		}
+
+		if !slices.Equal(items, expected) {
+			t.Errorf("expected %v, got %v", expected, items)
+		}
+	})
+
+	t.Run("renders links to the elements", func(t *testing.T) {
+		f := newForm().SetError("The form has errors")
+		result := cleanHTML(f.RenderErrorSummary())
+
+		expected := `<div id="signup-error-summary" role="alert" tabindex="-1" autofocus aria-labelledby="signup-error-summary-title">` +
+			`<h2 id="signup-error-summary-title">Fix the following</h2>` +
+			`<p>The form has errors</p>` +
+			`<ul>` +
+			`<li><a href="#username">Username: Invalid value</a></li>` +
+			`<li><a href="#email">Email address: Enter a valid email</a></li>` +
+			`<li><a href="#plan-0">Plan: Invalid value</a></li>` +
+			`<li><a href="#nickname">nickname: Too short</a></li>` +
+			`</ul></div>`
+
+		if result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, result)
+		}
+	})
+
+	t.Run("renders nothing for a pristine form", func(t *testing.T) {
+		f := Form().AddChildren(
+			Text("username").SetLabel("Username").SetAttributes(Attr("required", true)),
+			Radio("plan").SetAttributes(Attr("required", true)).SetOptions(Option("Free", "free")),
+		)
+
+		if result := cleanHTML(f.RenderErrorSummary()); result != "" {
+			t.Errorf("expected empty summary, got %s", result)
+		}
+	})
+
+	t.Run("renders nothing for a valid form", func(t *testing.T) {
+		f := Form().AddChildren(Text("username").SetLabel("Username"))
+
+		if result := cleanHTML(f.RenderErrorSummary()); result != "" {
+			t.Errorf("expected empty summary, got %s", result)
+		}
+	})
+}
+
+func TestForm_Bind(t *testing.T) {
+	name := Text("name")
+	name.SetValue("Jane")
+	f := Form().AddChildren(name)
+
+	t.Run("binds a pointer to a struct", func(t *testing.T) {
+		var data signup
+		if err := f.Bind(&data); err != nil || data.Name != "Jane" {
+			t.Errorf("expected Jane to be bound, got %q %v", data.Name, err)
+		}
+	})
+
+	t.Run("rejects other targets", func(t *testing.T) {
+		if err := f.Bind(signup{}); !errors.Is(err, ErrInvalidBindTarget) {
+			t.Errorf("expected ErrInvalidBindTarget, got %v", err)
+		}
+	})
+}
@@ -2,6 +2,8 @@ package goform
 
 import (
 	"html/template"
+	"io"
+	"strings"
 )
 
 func Group(children ...Renderer) *group {
@@ -21,6 +23,7 @@ func Group(children ...Renderer) *group {
 
 type group struct {
 	class      string
+	custom     string
 	children   []Renderer
 	attributes Attrs
 	renderer   TemplateRenderer
@@ -41,8 +44,48 @@ func (g *group) Children() []Renderer {
 	return g.children
 }
 
+func (g *group) templateName() string {
+	if g.custom != "" {
+		return g.custom
+	}
+	return "group.tmpl"
+}
+
+func (g *group) SetTemplate(name string) *group {
+	g.custom = strings.TrimSpace(name)
+	return g
+}
+
+// groupView is the data of the group template, it exposes the group along with the writer it is rendered to
+type groupView struct {
+	*group
+	w io.Writer
+}
+
+// RenderChild renders a child of the group straight into the writer of the group
+func (v groupView) RenderChild(child Renderer) (template.HTML, error) {
+	return renderChild(v.w, child)
+}
+
 func (g *group) Render() template.HTML {
-	return g.renderer.Render("group.tmpl", g)
+	h, err := g.RenderE()
+	if err != nil {
+		return renderErrorHTML(err)
+	}
+	return h
+}
+
+func (g *group) RenderE() (template.HTML, error) {
+	return renderContainer(g)
+}
+
+func (g *group) RenderTo(w io.Writer) error {
+	return g.renderer.RenderTo(w, g.templateName(), groupView{group: g, w: w})
+}
+
+func (g *group) setRenderer(renderer TemplateRenderer) {
+	g.renderer = renderer
+	applyRenderer(renderer, g.children...)
 }
 
 var _ Container = (*group)(nil)
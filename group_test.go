@@ -3,6 +3,7 @@ package goform
 import (
 	"html/template"
 	"testing"
+	"testing/fstest"
 )
 
 func TestGroup_Creation(t *testing.T) {
@@ -382,3 +383,28 @@ func TestGroup_NilSafety(t *testing.T) {
 		}
 	})
 }
+
+func TestGroup_SetTemplate(t *testing.T) {
+	r, err := newTemplateRenderer(&templatesOverrideOptions{
+		filesystem: fstest.MapFS{
+			"toolbar.tmpl": &fstest.MapFile{Data: []byte(`<nav>{{ range .Children }}{{ form_component . }}{{ end }}</nav>`)},
+		},
+		patterns: []string{"*.tmpl"},
+	}, ThemeDefault)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	g := Group(Submit("save").SetAttributes(Id("save"))).SetTemplate("toolbar.tmpl")
+	g.setRenderer(r)
+
+	result, err := g.RenderE()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `<nav><input id="save" name="save" type="submit"></nav>`
+	if cleanHTML(result) != expected {
+		t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, cleanHTML(result))
+	}
+}
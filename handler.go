@@ -0,0 +1,285 @@
+package goform
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const defaultFailureMessage = "The form could not be submitted, please try again."
+
+// SubmissionError rejects a submission with a message shown to the user,
+// Handler replaces the other errors returned by onValid with a generic message
+type SubmissionError struct {
+	Message string
+}
+
+func (e *SubmissionError) Error() string {
+	return e.Message
+}
+
+// HandledForm is the form built for each request handled by Handler
+type HandledForm interface {
+	Renderer
+	Method() string
+	IssueCSRFToken(w http.ResponseWriter, r *http.Request) error
+	PopulateFromRequest(r *http.Request) error
+	IsValid() (bool, map[string]string)
+	Bind(obj any) error
+	Reject(message string)
+	SaveFlash(w http.ResponseWriter, r *http.Request) (bool, error)
+	RestoreFlash(w http.ResponseWriter, r *http.Request) error
+}
+
+// FormPage is the data the page template is executed with
+type FormPage struct {
+	Form    HandledForm
+	Request *http.Request
+}
+
+// HandlerOption defines a functional option for configuring form handlers
+type HandlerOption func(*handlerOptions)
+
+type handlerOptions struct {
+	redirect       string       // Location of the redirection after a successful submission
+	templateName   string       // Template executed to render the page
+	failureMessage string       // Form error shown when onValid fails unexpectedly
+	logger         *slog.Logger // Logs the errors that are not shown to the user
+}
+
+// WithRedirect sets the location the browser is redirected to after a successful submission,
+// it defaults to the URL of the request
+func WithRedirect(location string) HandlerOption {
+	return func(options *handlerOptions) {
+		options.redirect = location
+	}
+}
+
+// WithTemplateName executes the named template of the page template set
+func WithTemplateName(name string) HandlerOption {
+	return func(options *handlerOptions) {
+		options.templateName = name
+	}
+}
+
+// WithFailureMessage sets the form error shown when onValid returns an error other than a SubmissionError
+func WithFailureMessage(message string) HandlerOption {
+	return func(options *handlerOptions) {
+		options.failureMessage = message
+	}
+}
+
+// WithLogger logs the errors that are not shown to the user, it defaults to slog.Default
+func WithLogger(logger *slog.Logger) HandlerOption {
+	return func(options *handlerOptions) {
+		options.logger = logger
+	}
+}
+
+type formHandler[T any] struct {
+	newForm func(r *http.Request) HandledForm
+	onValid func(ctx context.Context, data *T) error
+	page    *template.Template
+	options handlerOptions
+}
+
+// Handler renders the form on GET and validates it on submission.
+// A valid submission is bound to T and passed to onValid, then the browser is redirected (Post/Redirect/Get).
+// An invalid submission or a SubmissionError re-renders the page with the errors and the status 422,
+// the other errors of onValid are logged and re-render the page with a generic message and the status 500.
+// Forms with the GET method are validated when the query string is not empty and are never redirected.
+// Forms configured WithFlash redirect invalid submissions too and restore their state on the next GET.
+func Handler[T any](
+	newForm func(r *http.Request) HandledForm,
+	onValid func(ctx context.Context, data *T) error,
+	page *template.Template,
+	options ...HandlerOption,
+) http.Handler {
+	h := &formHandler[T]{
+		newForm: newForm,
+		onValid: onValid,
+		page:    page,
+		options: handlerOptions{
+			failureMessage: defaultFailureMessage,
+			logger:         slog.Default(),
+		},
+	}
+	for _, option := range options {
+		option(&h.options)
+	}
+	return h
+}
+
+func (h *formHandler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f := h.newForm(r)
+	method := f.Method()
+
+	if method == http.MethodGet {
+		h.serveGetForm(w, r, f)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		if r.Method == http.MethodGet {
+			// an expired or tampered state renders the blank form
+			if err := f.RestoreFlash(w, r); err != nil && !errors.Is(err, ErrInvalidFlash) {
+				h.options.logger.ErrorContext(r.Context(), "failed to restore the flash state", "error", err)
+			}
+		}
+		h.render(w, r, f, http.StatusOK)
+	case r.Method == method || (r.Method == http.MethodPost && method != http.MethodPost):
+		h.submit(w, r, f)
+	default:
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodHead, method}, ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *formHandler[T]) serveGetForm(w http.ResponseWriter, r *http.Request, f HandledForm) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodHead}, ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.URL.RawQuery == "" {
+		h.render(w, r, f, http.StatusOK)
+		return
+	}
+
+	if err := f.PopulateFromRequest(r); err != nil {
+		h.fail(w, r, f, err)
+		return
+	}
+
+	h.render(w, r, f, h.validate(r, f))
+}
+
+func (h *formHandler[T]) submit(w http.ResponseWriter, r *http.Request, f HandledForm) {
+	if err := f.PopulateFromRequest(r); err != nil {
+		h.fail(w, r, f, err)
+		return
+	}
+
+	// browsers submit the overridden methods with POST
+	if r.Method == http.MethodPost && f.Method() != http.MethodPost {
+		if !strings.EqualFold(r.PostForm.Get(MethodOverrideFieldName), f.Method()) {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+	}
+
+	if status := h.validate(r, f); status != http.StatusOK {
+		// the state is rendered directly when it cannot be saved
+		if status == http.StatusUnprocessableEntity {
+			saved, err := f.SaveFlash(w, r)
+			if err != nil {
+				h.options.logger.ErrorContext(r.Context(), "failed to save the flash state", "error", err)
+			}
+			if saved && err == nil {
+				http.Redirect(w, r, r.URL.RequestURI(), http.StatusSeeOther)
+				return
+			}
+		}
+		h.render(w, r, f, status)
+		return
+	}
+
+	h.redirect(w, r)
+}
+
+// validate binds a valid form and passes it to the callback, it returns the status of the response
+func (h *formHandler[T]) validate(r *http.Request, f HandledForm) int {
+	if isValid, _ := f.IsValid(); !isValid {
+		return http.StatusUnprocessableEntity
+	}
+
+	var data T
+	if err := f.Bind(&data); err != nil {
+		h.options.logger.ErrorContext(r.Context(), "failed to bind the form", "error", err)
+		f.Reject(h.options.failureMessage)
+		return http.StatusInternalServerError
+	}
+
+	if err := h.onValid(r.Context(), &data); err != nil {
+		// only submission errors are meant for the user, the others may leak internal details
+		var submissionErr *SubmissionError
+		if errors.As(err, &submissionErr) {
+			f.Reject(submissionErr.Message)
+			return http.StatusUnprocessableEntity
+		}
+
+		h.options.logger.ErrorContext(r.Context(), "failed to handle the form submission", "error", err)
+		f.Reject(h.options.failureMessage)
+		return http.StatusInternalServerError
+	}
+
+	return http.StatusOK
+}
+
+func (h *formHandler[T]) fail(w http.ResponseWriter, r *http.Request, f HandledForm, err error) {
+	switch {
+	case errors.Is(err, ErrSpamDetected):
+		// bots are not told that their submission was rejected
+		h.redirect(w, r)
+	case errors.Is(err, ErrInvalidCSRFToken):
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	case errors.Is(err, ErrBodyTooLarge):
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, ErrUnsupportedContentType):
+		http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+	case errors.Is(err, ErrUnknownFields):
+		h.render(w, r, f, http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	}
+}
+
+func (h *formHandler[T]) redirect(w http.ResponseWriter, r *http.Request) {
+	location := h.options.redirect
+	if location == "" {
+		location = r.URL.RequestURI()
+	}
+	http.Redirect(w, r, location, http.StatusSeeOther)
+}
+
+func (h *formHandler[T]) render(w http.ResponseWriter, r *http.Request, f HandledForm, status int) {
+	if err := f.IssueCSRFToken(w, r); err != nil {
+		h.options.logger.ErrorContext(r.Context(), "failed to issue the csrf token", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	data := FormPage{
+		Form:    f,
+		Request: r,
+	}
+
+	var err error
+	if h.options.templateName != "" {
+		err = h.page.ExecuteTemplate(buf, h.options.templateName, data)
+	} else {
+		err = h.page.Execute(buf, data)
+	}
+	if err != nil {
+		h.options.logger.ErrorContext(r.Context(), "failed to render the form page", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := buf.WriteTo(w); err != nil {
+		h.options.logger.ErrorContext(r.Context(), "failed to write the form page", "error", err)
+	}
+}
@@ -0,0 +1,188 @@
+package goform
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type signup struct {
+	Name string `goform:"name"`
+}
+
+func TestHandler(t *testing.T) {
+	page := template.Must(template.New("page").Funcs(template.FuncMap{
+		"form": FormRenderer(),
+	}).Parse(`{{ form .Form }}`))
+
+	var saved []signup
+	onValid := func(_ context.Context, data *signup) error {
+		switch data.Name {
+		case "taken":
+			return &SubmissionError{Message: "This name is taken"}
+		case "broken":
+			return errors.New("pq: connection refused")
+		}
+		saved = append(saved, *data)
+		return nil
+	}
+
+	newHandler := func(method string, options ...HandlerOption) http.Handler {
+		return Handler(func(_ *http.Request) HandledForm {
+			return Form().
+				SetAttributes(Id("signup"), Attr("method", method)).
+				AddChildren(Text("name").SetAttributes(Id("name"), Attr("required", true)))
+		}, onValid, page, options...)
+	}
+
+	serve := func(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("renders the form on GET", func(t *testing.T) {
+		rec := serve(newHandler(http.MethodPost), httptest.NewRequest(http.MethodGet, "/signup", nil))
+
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `<form enctype="application/x-www-form-urlencoded" id="signup" method="POST">`) {
+			t.Errorf("expected the form, got %d %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("redirects after a valid submission", func(t *testing.T) {
+		saved = nil
+		rec := serve(newHandler(http.MethodPost, WithRedirect("/welcome")), postForm(url.Values{"name": {"Jane"}}))
+
+		if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/welcome" {
+			t.Errorf("expected a redirection to /welcome, got %d %s", rec.Code, rec.Header().Get("Location"))
+		}
+		if len(saved) != 1 || saved[0].Name != "Jane" {
+			t.Errorf("expected Jane to be saved, got %v", saved)
+		}
+	})
+
+	t.Run("redirects to the same URL by default", func(t *testing.T) {
+		req := postForm(url.Values{"name": {"Jane"}})
+		req.URL.Path = "/signup"
+
+		rec := serve(newHandler(http.MethodPost), req)
+		if rec.Header().Get("Location") != "/signup" {
+			t.Errorf("expected a redirection to /signup, got %s", rec.Header().Get("Location"))
+		}
+	})
+
+	t.Run("re-renders an invalid submission", func(t *testing.T) {
+		rec := serve(newHandler(http.MethodPost), postForm(url.Values{"name": {""}}))
+
+		if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `aria-invalid="true"`) {
+			t.Errorf("expected the invalid form, got %d %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("re-renders the callback error", func(t *testing.T) {
+		rec := serve(newHandler(http.MethodPost), postForm(url.Values{"name": {"taken"}}))
+
+		if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "This name is taken") {
+			t.Errorf("expected the callback error, got %d %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("hides unexpected callback errors", func(t *testing.T) {
+		var logs strings.Builder
+		logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+		rec := serve(newHandler(http.MethodPost, WithLogger(logger)), postForm(url.Values{"name": {"broken"}}))
+
+		body := rec.Body.String()
+		if rec.Code != http.StatusInternalServerError || strings.Contains(body, "pq:") || !strings.Contains(body, defaultFailureMessage) {
+			t.Errorf("expected the generic message, got %d %s", rec.Code, body)
+		}
+		if !strings.Contains(logs.String(), "pq: connection refused") {
+			t.Errorf("expected the error to be logged, got %s", logs.String())
+		}
+	})
+
+	t.Run("shows the configured failure message", func(t *testing.T) {
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		h := newHandler(http.MethodPost, WithLogger(logger), WithFailureMessage("Please retry later"))
+
+		rec := serve(h, postForm(url.Values{"name": {"broken"}}))
+		if !strings.Contains(rec.Body.String(), "Please retry later") {
+			t.Errorf("expected the configured message, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("rejects other methods", func(t *testing.T) {
+		rec := serve(newHandler(http.MethodPost), httptest.NewRequest(http.MethodDelete, "/", nil))
+
+		if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, HEAD, POST" {
+			t.Errorf("expected 405, got %d %s", rec.Code, rec.Header().Get("Allow"))
+		}
+	})
+
+	t.Run("method override", func(t *testing.T) {
+		h := newHandler(http.MethodPut)
+
+		rec := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+		if !strings.Contains(rec.Body.String(), `<input name="_method" type="hidden" value="PUT">`) ||
+			!strings.Contains(rec.Body.String(), `method="POST"`) {
+			t.Errorf("expected the method override field, got %s", rec.Body.String())
+		}
+
+		rec = serve(h, postForm(url.Values{"name": {"Jane"}, MethodOverrideFieldName: {"PUT"}}))
+		if rec.Code != http.StatusSeeOther {
+			t.Errorf("expected an overridden submission to be handled, got %d", rec.Code)
+		}
+
+		rec = serve(h, postForm(url.Values{"name": {"Jane"}}))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected a POST without override to be rejected, got %d", rec.Code)
+		}
+
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"name": "Jane"}`))
+		req.Header.Set("Content-Type", JSONData)
+		if rec = serve(h, req); rec.Code != http.StatusSeeOther {
+			t.Errorf("expected a PUT to be handled, got %d", rec.Code)
+		}
+	})
+
+	t.Run("GET forms are validated from the query string", func(t *testing.T) {
+		saved = nil
+		h := newHandler(http.MethodGet)
+
+		if rec := serve(h, httptest.NewRequest(http.MethodGet, "/search", nil)); rec.Code != http.StatusOK || len(saved) != 0 {
+			t.Errorf("expected the empty form, got %d", rec.Code)
+		}
+		if rec := serve(h, httptest.NewRequest(http.MethodGet, "/search?name=", nil)); rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected 422, got %d", rec.Code)
+		}
+		if rec := serve(h, httptest.NewRequest(http.MethodGet, "/search?name=go", nil)); rec.Code != http.StatusOK || len(saved) != 1 {
+			t.Errorf("expected the query to be handled, got %d", rec.Code)
+		}
+		if rec := serve(h, postForm(url.Values{"name": {"go"}})); rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected 405, got %d", rec.Code)
+		}
+	})
+
+	t.Run("maps population errors to status codes", func(t *testing.T) {
+		csrf := Handler(func(_ *http.Request) HandledForm {
+			return Form(WithCSRF(&CookieTokenStore{})).AddChildren(Text("name"))
+		}, onValid, page)
+		if rec := serve(csrf, postForm(url.Values{"name": {"Jane"}})); rec.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rec.Code)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("<name/>"))
+		req.Header.Set("Content-Type", "text/xml")
+		if rec := serve(newHandler(http.MethodPost), req); rec.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("expected 415, got %d", rec.Code)
+		}
+	})
+}
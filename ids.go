@@ -0,0 +1,135 @@
+package goform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type IdGenerator func(formId string, path []string) string
+
+var invalidIdChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+func sanitizeId(value string) string {
+	return strings.Trim(invalidIdChars.ReplaceAllString(value, "-"), "-")
+}
+
+// NameBasedIds derives element IDs from the form ID and the element path, e.g. login-username.
+// The path holds the IDs of the enclosing fieldsets and groups followed by the element name.
+func NameBasedIds(formId string, path []string) string {
+	parts := make([]string, 0, len(path)+1)
+	for _, part := range append([]string{formId}, path...) {
+		if part := sanitizeId(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// WithIdGenerator generates the IDs of the elements the form contains,
+// IDs set explicitly on elements are left untouched. The form must have an explicit ID,
+// RenderE and RenderTo return ErrMissingFormId otherwise.
+func WithIdGenerator(generator IdGenerator) FormOption {
+	return func(options *formOptions) {
+		options.idGenerator = generator
+	}
+}
+
+// WithNameBasedIds derives element IDs from the form ID and the element names,
+// the form must have an explicit ID
+func WithNameBasedIds() FormOption {
+	return WithIdGenerator(NameBasedIds)
+}
+
+func reservedIds(e *element) []string {
+	if options := e.flatOptions(); e.attributes.String("type") == InputTypeRadio && len(options) > 0 {
+		ids := make([]string, 0, len(options))
+		for i := range options {
+			ids = append(ids, fmt.Sprintf("%s-%d", e.Id(), i))
+		}
+		return ids
+	}
+	return []string{e.Id()}
+}
+
+func uniqueId(id string, used map[string]bool) string {
+	candidate := id
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d", id, i)
+	}
+	return candidate
+}
+
+// containerPath returns the IDs of the containers enclosing an element
+func containerPath(parents []Renderer) []string {
+	path := make([]string, 0, len(parents))
+	for _, parent := range parents {
+		var id string
+		switch parent := parent.(type) {
+		case *fieldSet:
+			id = parent.attributes.String("id")
+		case *group:
+			id = parent.attributes.String("id")
+		}
+		if id != "" {
+			path = append(path, id)
+		}
+	}
+	return path
+}
+
+// verifyFormId prevents rendering generated IDs derived from a random form ID,
+// they would change on every render and collide between forms with the same elements
+func (f *form) verifyFormId() error {
+	if f.options.idGenerator != nil && !f.hasExplicitId() {
+		return fmt.Errorf("%w: element ids are derived from the form id", ErrMissingFormId)
+	}
+	return nil
+}
+
+func (f *form) assignIds() {
+	f.syncHoneypotId()
+
+	if f.options.idGenerator == nil {
+		return
+	}
+
+	used := map[string]bool{
+		f.Id(): true,
+	}
+	generated := make([]*element, 0)
+	paths := make(map[*element][]string)
+
+	walk(f.children, nil, func(node Renderer, parents []Renderer) {
+		switch node := node.(type) {
+		case *fieldSet:
+			used[node.attributes.String("id")] = true
+		case *group:
+			used[node.attributes.String("id")] = true
+		case *element:
+			if node.autoId != "" && node.Id() == node.autoId {
+				generated = append(generated, node)
+				paths[node] = append(containerPath(parents), node.Name())
+				return
+			}
+			for _, id := range reservedIds(node) {
+				used[id] = true
+			}
+		}
+	})
+
+	for _, e := range generated {
+		id := f.options.idGenerator(f.Id(), paths[e])
+		if id == "" {
+			id = GenId()
+		}
+
+		id = uniqueId(id, used)
+		e.autoId = id
+		e.SetAttributes(Id(id))
+
+		for _, reserved := range reservedIds(e) {
+			used[reserved] = true
+		}
+	}
+}
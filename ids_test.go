@@ -0,0 +1,183 @@
+package goform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNameBasedIds(t *testing.T) {
+	tests := []struct {
+		name     string
+		formId   string
+		path     []string
+		expected string
+	}{
+		{"simple name", "login", []string{"username"}, "login-username"},
+		{"nested path", "profile", []string{"address", "city"}, "profile-address-city"},
+		{"brackets", "order", []string{"items[0][sku]"}, "order-items-0-sku"},
+		{"spaces", "search", []string{" full name "}, "search-full-name"},
+		{"empty form id", "", []string{"email"}, "email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if id := NameBasedIds(tt.formId, tt.path); id != tt.expected {
+				t.Errorf("expected id=%s, got %s", tt.expected, id)
+			}
+		})
+	}
+}
+
+func TestForm_NameBasedIds(t *testing.T) {
+	t.Run("ids are derived from the form id and element names", func(t *testing.T) {
+		username := Text("username").SetHint("Your handle")
+		password := Password("password")
+
+		Form(WithNameBasedIds()).
+			SetAttributes(Id("login")).
+			AddChildren(FieldSet("Credentials", username, password))
+
+		if username.Id() != "login-username" {
+			t.Errorf("expected id=login-username, got %s", username.Id())
+		}
+		if password.Id() != "login-password" {
+			t.Errorf("expected id=login-password, got %s", password.Id())
+		}
+		if v := username.Attributes().String(AriaDescribedByAttribute); v != "login-username-hint" {
+			t.Errorf("expected aria-describedby to follow the id, got %s", v)
+		}
+	})
+
+	t.Run("renders are identical", func(t *testing.T) {
+		build := func() string {
+			f := Form(WithNameBasedIds()).
+				SetAttributes(Id("login")).
+				AddChildren(Text("username").SetLabel("Username"))
+			return string(f.Render())
+		}
+
+		if build() != build() {
+			t.Error("expected identical renders")
+		}
+	})
+
+	t.Run("rendering requires an explicit form id", func(t *testing.T) {
+		f := Form(WithNameBasedIds()).AddChildren(Hidden("id"))
+
+		if _, err := f.RenderE(); !errors.Is(err, ErrMissingFormId) {
+			t.Errorf("expected ErrMissingFormId, got %v", err)
+		}
+
+		first := Form(WithNameBasedIds()).SetAttributes(Id("delete-1")).AddChildren(Hidden("id"))
+		second := Form(WithNameBasedIds()).SetAttributes(Id("delete-2")).AddChildren(Hidden("id"))
+		if a, b := first.Elements()["id"].(*element).Id(), second.Elements()["id"].(*element).Id(); a == b {
+			t.Errorf("expected distinct ids, got %s and %s", a, b)
+		}
+	})
+
+	t.Run("the path holds the ids of the enclosing containers", func(t *testing.T) {
+		var paths [][]string
+		generator := func(formId string, path []string) string {
+			paths = append(paths, path)
+			return NameBasedIds(formId, path)
+		}
+
+		billing := Text("billing.city")
+		shipping := Text("shipping.city")
+
+		Form(WithIdGenerator(generator)).
+			SetAttributes(Id("checkout")).
+			AddChildren(
+				FieldSet("Billing", billing).SetAttributes(Id("billing")),
+				FieldSet("Shipping", Group(shipping).SetAttributes(Id("address"))).SetAttributes(Id("shipping")),
+			)
+
+		if billing.Id() != "checkout-billing-billing-city" {
+			t.Errorf("expected id=checkout-billing-billing-city, got %s", billing.Id())
+		}
+		if shipping.Id() != "checkout-shipping-address-shipping-city" {
+			t.Errorf("expected id=checkout-shipping-address-shipping-city, got %s", shipping.Id())
+		}
+		if last := paths[len(paths)-1]; strings.Join(last, "/") != "shipping/address/shipping.city" {
+			t.Errorf("expected the container path, got %v", last)
+		}
+	})
+
+	t.Run("collisions are suffixed", func(t *testing.T) {
+		first := Checkbox("tags")
+		second := Checkbox("tags")
+		third := Checkbox("tags")
+
+		Form(WithNameBasedIds()).
+			SetAttributes(Id("post")).
+			AddChildren(Text("other").SetAttributes(Id("post-tags")), first, second, third)
+
+		got := []string{first.Id(), second.Id(), third.Id()}
+		expected := []string{"post-tags-2", "post-tags-3", "post-tags-4"}
+		if strings.Join(got, " ") != strings.Join(expected, " ") {
+			t.Errorf("expected ids=%v, got %v", expected, got)
+		}
+	})
+
+	t.Run("radio option ids are reserved", func(t *testing.T) {
+		plan := Radio("plan").SetOptions(Option("Free", "free"), Option("Pro", "pro"))
+		clash := Text("plan-0")
+
+		Form(WithNameBasedIds()).
+			SetAttributes(Id("")).
+			AddChildren(plan, clash)
+
+		if plan.Id() != "plan" {
+			t.Errorf("expected id=plan, got %s", plan.Id())
+		}
+		if clash.Id() != "plan-0-2" {
+			t.Errorf("expected id=plan-0-2, got %s", clash.Id())
+		}
+	})
+
+	t.Run("explicit ids are kept", func(t *testing.T) {
+		email := Email("email").SetAttributes(Id("contact-email"))
+
+		Form(WithNameBasedIds()).
+			SetAttributes(Id("signup")).
+			AddChildren(email)
+
+		if email.Id() != "contact-email" {
+			t.Errorf("expected id=contact-email, got %s", email.Id())
+		}
+	})
+
+	t.Run("changing the form id re-derives the ids", func(t *testing.T) {
+		email := Email("email")
+
+		f := Form(WithNameBasedIds()).AddChildren(email)
+		f.SetAttributes(Id("newsletter"))
+
+		if email.Id() != "newsletter-email" {
+			t.Errorf("expected id=newsletter-email, got %s", email.Id())
+		}
+	})
+
+	t.Run("default forms keep random ids", func(t *testing.T) {
+		email := Email("email")
+		Form().SetAttributes(Id("newsletter")).AddChildren(email)
+
+		if email.Id() == "newsletter-email" {
+			t.Error("expected a generated id without an id generator")
+		}
+	})
+}
+
+func TestForm_WithIdGenerator(t *testing.T) {
+	name := Text("name")
+	Form(WithIdGenerator(func(formId string, path []string) string {
+		return "f_" + formId + "_" + strings.Join(path, "_")
+	})).
+		SetAttributes(Id("contact")).
+		AddChildren(name)
+
+	if name.Id() != "f_contact_name" {
+		t.Errorf("expected id=f_contact_name, got %s", name.Id())
+	}
+}
@@ -0,0 +1,213 @@
+package goform
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+func Option(label, value string) option {
+	return option{
+		Label: strings.TrimSpace(label),
+		Value: strings.TrimSpace(value),
+	}
+}
+
+// OptGroup groups select options under a label
+func OptGroup(label string, options ...option) option {
+	return option{
+		Label:   strings.TrimSpace(label),
+		Options: options,
+	}
+}
+
+type option struct {
+	Label      string
+	Value      string
+	Hint       string
+	Disabled   bool
+	Checked    bool
+	Attributes Attrs
+	Options    []option
+}
+
+func (o option) SetHint(value string) option {
+	o.Hint = strings.TrimSpace(value)
+	return o
+}
+
+func (o option) SetDisabled(disabled bool) option {
+	o.Disabled = disabled
+	return o
+}
+
+// SetChecked checks a radio option or selects a select option
+func (o option) SetChecked(checked bool) option {
+	o.Checked = checked
+	return o
+}
+
+func (o option) SetAttributes(modifiers ...attrModifier) option {
+	// options are values, the attributes are copied so that they are not shared
+	attributes := maps.Clone(o.Attributes)
+	if attributes == nil {
+		attributes = Attributes()
+	}
+	for _, mod := range modifiers {
+		mod(attributes)
+	}
+	o.Attributes = attributes
+	return o
+}
+
+func (o option) IsGroup() bool {
+	return len(o.Options) > 0
+}
+
+func (o option) isChecked(values []string) bool {
+	if len(values) > 0 {
+		return slices.Contains(values, o.Value)
+	}
+	return o.Checked
+}
+
+// RadioOption is a radio option ready to be rendered,
+// its attributes combine the element attributes with the option state
+type RadioOption struct {
+	Id         string
+	Label      string
+	Hint       string
+	Attributes Attrs
+	invalid    bool
+}
+
+func (o RadioOption) IsInvalid() bool {
+	return o.invalid
+}
+
+// SelectOption is a select option or optgroup ready to be rendered,
+// the value is kept apart from the attributes since an empty value is meaningful
+type SelectOption struct {
+	Label      string
+	Value      string
+	Attributes Attrs
+	Options    []SelectOption
+}
+
+func (o SelectOption) IsGroup() bool {
+	return len(o.Options) > 0
+}
+
+// flatOptions returns the options without their groups
+func (e *element) flatOptions() []option {
+	options := make([]option, 0, len(e.options))
+	for _, opt := range e.options {
+		if opt.IsGroup() {
+			options = append(options, opt.Options...)
+		} else {
+			options = append(options, opt)
+		}
+	}
+	return options
+}
+
+// RadioOptions returns the options of a radio group, each option shares the element attributes.
+// The submitted value takes precedence over the options checked state.
+func (e *element) RadioOptions() []RadioOption {
+	values := e.Values()
+	flat := e.flatOptions()
+	options := make([]RadioOption, len(flat))
+
+	for i, opt := range flat {
+		id := fmt.Sprintf("%s-%d", e.Id(), i)
+
+		attributes := maps.Clone(e.attributes)
+		maps.Copy(attributes, opt.Attributes)
+		// the id is assigned directly, the option keeps referring to the group descriptions
+		attributes["id"] = id
+		attributes.Set("value", opt.Value)
+
+		if opt.isChecked(values) {
+			attributes.Set("checked", true)
+		} else {
+			attributes.Unset("checked")
+		}
+		if opt.Disabled {
+			attributes.Set("disabled", true)
+		}
+		if opt.Hint != "" {
+			attributes.AddTokens(AriaDescribedByAttribute, fmt.Sprintf(AriaHintTemplate, id))
+		}
+
+		options[i] = RadioOption{
+			Id:         id,
+			Label:      opt.Label,
+			Hint:       opt.Hint,
+			Attributes: attributes,
+			invalid:    e.IsInvalid(),
+		}
+	}
+
+	return options
+}
+
+// SelectOptions returns the options of a select element, preceded by the placeholder if any.
+// The submitted value takes precedence over the options selected state.
+func (e *element) SelectOptions() []SelectOption {
+	values := e.Values()
+	options := make([]SelectOption, 0, len(e.options)+1)
+
+	if e.placeholder != "" {
+		attributes := Attributes()
+		if !slices.ContainsFunc(e.flatOptions(), func(opt option) bool { return opt.isChecked(values) }) {
+			attributes.Set("selected", true)
+		}
+		options = append(options, SelectOption{
+			Label:      e.placeholder,
+			Attributes: attributes,
+		})
+	}
+
+	for _, opt := range e.options {
+		options = append(options, newSelectOption(opt, values))
+	}
+
+	return options
+}
+
+func newSelectOption(opt option, values []string) SelectOption {
+	attributes := maps.Clone(opt.Attributes)
+	if attributes == nil {
+		attributes = Attributes()
+	}
+	if opt.Disabled {
+		attributes.Set("disabled", true)
+	}
+
+	if opt.IsGroup() {
+		attributes.Set("label", opt.Label)
+
+		options := make([]SelectOption, len(opt.Options))
+		for i, child := range opt.Options {
+			options[i] = newSelectOption(child, values)
+		}
+
+		return SelectOption{
+			Label:      opt.Label,
+			Attributes: attributes,
+			Options:    options,
+		}
+	}
+
+	attributes.Unset("value")
+	if opt.isChecked(values) {
+		attributes.Set("selected", true)
+	}
+
+	return SelectOption{
+		Label:      opt.Label,
+		Value:      opt.Value,
+		Attributes: attributes,
+	}
+}
@@ -0,0 +1,159 @@
+package goform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrBodyTooLarge           = errors.New("request body is too large")
+	ErrMalformedMultipart     = errors.New("malformed multipart form data")
+	ErrMalformedForm          = errors.New("malformed form data")
+	ErrMalformedJSON          = errors.New("malformed json body")
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+)
+
+// defaultMaxJSONSize caps JSON bodies without WithMaxBodySize like ParseForm caps URL-encoded bodies
+const defaultMaxJSONSize = 10 << 20
+
+// WithMaxBodySize limits the size of the request body read by PopulateFromRequest,
+// larger bodies are rejected with ErrBodyTooLarge
+func WithMaxBodySize(size int64) FormOption {
+	return func(options *formOptions) {
+		options.maxBodySize = size
+	}
+}
+
+func classifyParseError(err error, malformed error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return fmt.Errorf("%w: %w", ErrBodyTooLarge, err)
+	}
+	return fmt.Errorf("%w: %w", malformed, err)
+}
+
+func (f *form) isGetForm() bool {
+	return strings.EqualFold(f.attributes.String("method"), http.MethodGet)
+}
+
+// parseRequest parses the request according to the form method and the content type of the body.
+// It returns the submitted values, which are both verified and bound: the query string of
+// a request with a body is not part of the form and is never bound.
+func (f *form) parseRequest(r *http.Request) (url.Values, error) {
+	if f.isGetForm() {
+		return r.URL.Query(), nil
+	}
+
+	if f.options.maxBodySize > 0 && r.Body != nil && r.PostForm == nil {
+		r.Body = http.MaxBytesReader(nil, r.Body, f.options.maxBodySize)
+	}
+
+	mediaType := ""
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnsupportedContentType, err)
+		}
+	}
+
+	hasBody := !isSafeMethod(r.Method) && r.PostForm == nil && r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+
+	switch {
+	case mediaType == JSONData && hasBody:
+		if f.options.maxBodySize <= 0 {
+			r.Body = http.MaxBytesReader(nil, r.Body, defaultMaxJSONSize)
+		}
+		values, err := decodeJSON(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		return values, nil
+	case mediaType == MultipartData:
+		if err := r.ParseForm(); err != nil {
+			return nil, classifyParseError(err, ErrMalformedForm)
+		}
+		if r.MultipartForm == nil {
+			if err := r.ParseMultipartForm(f.options.maxMemory); err != nil {
+				return nil, classifyParseError(err, ErrMalformedMultipart)
+			}
+		}
+	case mediaType == URLEncodedData, !hasBody:
+		if err := r.ParseForm(); err != nil {
+			return nil, classifyParseError(err, ErrMalformedForm)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, mediaType)
+	}
+
+	if isSafeMethod(r.Method) {
+		return r.Form, nil
+	}
+	return r.PostForm, nil
+}
+
+// decodeJSON maps a JSON object onto element names, nested objects are flattened with dots
+// and arrays become multiple values, e.g. {"address": {"city": "Paris"}} populates address.city
+func decodeJSON(body io.Reader) (url.Values, error) {
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+
+	var data map[string]any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, classifyParseError(err, ErrMalformedJSON)
+	}
+
+	values := make(url.Values)
+	if err := flattenJSON(values, "", data); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func flattenJSON(values url.Values, prefix string, data map[string]any) error {
+	for key, value := range data {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+
+		switch value := value.(type) {
+		case map[string]any:
+			if err := flattenJSON(values, name, value); err != nil {
+				return err
+			}
+		case []any:
+			for _, item := range value {
+				s, ok := jsonScalar(item)
+				if !ok {
+					return fmt.Errorf("%w: field %s contains an unsupported value", ErrMalformedJSON, name)
+				}
+				values.Add(name, s)
+			}
+		default:
+			// null values are skipped
+			if s, ok := jsonScalar(value); ok {
+				values.Add(name, s)
+			}
+		}
+	}
+	return nil
+}
+
+func jsonScalar(value any) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case json.Number:
+		return value.String(), true
+	case bool:
+		return strconv.FormatBool(value), true
+	}
+	return "", false
+}
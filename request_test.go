@@ -0,0 +1,220 @@
+package goform
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestForm_PopulateFromRequestErrors(t *testing.T) {
+	t.Run("body too large", func(t *testing.T) {
+		values := url.Values{"bio": {strings.Repeat("a", 1024)}}
+		f := Form(WithMaxBodySize(512)).AddChildren(Textarea("bio"))
+
+		if err := f.PopulateFromRequest(postForm(values)); !errors.Is(err, ErrBodyTooLarge) {
+			t.Errorf("expected ErrBodyTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("multipart body too large", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("avatar", "avatar.png")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := part.Write(bytes.Repeat([]byte("a"), 4096)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		f := Form(WithMaxBodySize(1024)).AddChildren(File("avatar"))
+		if err := f.PopulateFromRequest(req); !errors.Is(err, ErrBodyTooLarge) {
+			t.Errorf("expected ErrBodyTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("body within the limit", func(t *testing.T) {
+		f := Form(WithMaxBodySize(512)).AddChildren(Text("name"))
+
+		if err := f.PopulateFromRequest(postForm(url.Values{"name": {"Jane"}})); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v := f.Elements()["name"].Value(); v != "Jane" {
+			t.Errorf("expected name=Jane, got %s", v)
+		}
+	})
+
+	t.Run("malformed multipart", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("--oops\r\nnot a part"))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+
+		if err := Form().PopulateFromRequest(req); !errors.Is(err, ErrMalformedMultipart) {
+			t.Errorf("expected ErrMalformedMultipart, got %v", err)
+		}
+	})
+
+	t.Run("malformed url encoded form", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=%zz"))
+		req.Header.Set("Content-Type", URLEncodedData)
+
+		if err := Form().PopulateFromRequest(req); !errors.Is(err, ErrMalformedForm) {
+			t.Errorf("expected ErrMalformedForm, got %v", err)
+		}
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		for _, contentType := range []string{"text/xml", "", "not a media type;;"} {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("<name>Jane</name>"))
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+
+			if err := Form().PopulateFromRequest(req); !errors.Is(err, ErrUnsupportedContentType) {
+				t.Errorf("%q: expected ErrUnsupportedContentType, got %v", contentType, err)
+			}
+		}
+	})
+
+	t.Run("requests without body", func(t *testing.T) {
+		f := Form().AddChildren(Text("q"))
+
+		if err := f.PopulateFromRequest(httptest.NewRequest(http.MethodGet, "/?q=go", nil)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v := f.Elements()["q"].Value(); v != "go" {
+			t.Errorf("expected q=go, got %s", v)
+		}
+	})
+}
+
+func TestForm_PopulateFromValues(t *testing.T) {
+	f := Form().AddChildren(
+		Text("name").SetAttributes(Attr("required", true)),
+		Select("tags").SetAttributes(Attr("multiple", true)).SetOptions(Option("Go", "go"), Option("Rust", "rust")),
+	)
+
+	if err := f.PopulateFromValues(url.Values{"tags": {"go", "rust"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	elements := f.Elements()
+	if values := elements["tags"].(*element).Values(); len(values) != 2 {
+		t.Errorf("expected both tags, got %v", values)
+	}
+	if elements["name"].(*element).Attributes().String("aria-invalid") != "true" {
+		t.Error("expected the missing name to be marked as invalid")
+	}
+
+	strict := Form(WithStrictFields()).AddChildren(Text("name"))
+	if err := strict.PopulateFromValues(url.Values{"role": {"admin"}}); !errors.Is(err, ErrUnknownFields) {
+		t.Errorf("expected ErrUnknownFields, got %v", err)
+	}
+}
+
+func TestForm_PopulateGetForm(t *testing.T) {
+	f := Form().
+		SetAttributes(Attr("method", http.MethodGet)).
+		AddChildren(Text("q"), Text("page"))
+
+	req := httptest.NewRequest(http.MethodPost, "/search?q=go", strings.NewReader("page=2"))
+	req.Header.Set("Content-Type", URLEncodedData)
+
+	if err := f.PopulateFromRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	elements := f.Elements()
+	if v := elements["q"].Value(); v != "go" {
+		t.Errorf("expected q=go, got %s", v)
+	}
+	if v := elements["page"].Value(); v != "" {
+		t.Errorf("expected the body to be ignored, got page=%s", v)
+	}
+}
+
+func TestForm_PopulateFromJSON(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		return req
+	}
+
+	t.Run("maps the body onto element names", func(t *testing.T) {
+		f := Form().AddChildren(
+			Text("name"),
+			Number("age"),
+			Checkbox("terms"),
+			Text("address.city"),
+			Select("tags").SetAttributes(Attr("multiple", true)).SetOptions(Option("Go", "go"), Option("Rust", "rust")),
+			Text("nickname"),
+		)
+
+		body := `{"name": "Jane", "age": 42, "terms": true, "address": {"city": "Paris"}, "tags": ["go", "rust"], "nickname": null}`
+		if err := f.PopulateFromRequest(newRequest(body)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := map[string]string{
+			"name":         "Jane",
+			"age":          "42",
+			"terms":        "true",
+			"address.city": "Paris",
+			"tags":         "go",
+			"nickname":     "",
+		}
+		elements := f.Elements()
+		for name, value := range expected {
+			if v := elements[name].Value(); v != value {
+				t.Errorf("expected %s=%s, got %s", name, value, v)
+			}
+		}
+		if values := elements["tags"].(*element).Values(); len(values) != 2 {
+			t.Errorf("expected both tags, got %v", values)
+		}
+	})
+
+	t.Run("verifies the tokens of the body", func(t *testing.T) {
+		store := &CookieTokenStore{}
+		f := Form(WithCSRF(store), WithStrictFields()).AddChildren(Text("name"))
+
+		req := newRequest(`{"name": "Jane", "csrf_token": "token"}`)
+		req.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: "token"})
+
+		if err := f.PopulateFromRequest(req); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("malformed bodies", func(t *testing.T) {
+		for _, body := range []string{`{"name": `, `["Jane"]`, `{"tags": [{"id": 1}]}`} {
+			if err := Form().PopulateFromRequest(newRequest(body)); !errors.Is(err, ErrMalformedJSON) {
+				t.Errorf("%s: expected ErrMalformedJSON, got %v", body, err)
+			}
+		}
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		body := `{"name": "` + strings.Repeat("a", 1024) + `"}`
+		if err := Form(WithMaxBodySize(512)).PopulateFromRequest(newRequest(body)); !errors.Is(err, ErrBodyTooLarge) {
+			t.Errorf("expected ErrBodyTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("bodies are capped without a maximum size", func(t *testing.T) {
+		body := `{"name": "` + strings.Repeat("a", defaultMaxJSONSize) + `"}`
+		if err := Form().AddChildren(Text("name")).PopulateFromRequest(newRequest(body)); !errors.Is(err, ErrBodyTooLarge) {
+			t.Errorf("expected ErrBodyTooLarge, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package goform
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"slices"
+)
+
+const SignatureFieldSuffix = "_signature"
+
+var ErrMissingSigningKey = errors.New("signing key is missing")
+
+// Keyring signs values with its first key and verifies them with any of its keys,
+// new keys are added in front and old keys are kept until the values they signed expire
+type Keyring struct {
+	keys [][]byte
+}
+
+// NewKeyring panics when a key is empty because anyone could recompute the signatures
+func NewKeyring(current []byte, previous ...[]byte) *Keyring {
+	if len(current) == 0 || slices.ContainsFunc(previous, func(key []byte) bool { return len(key) == 0 }) {
+		panic(ErrMissingSigningKey)
+	}
+	return &Keyring{
+		keys: append([][]byte{current}, previous...),
+	}
+}
+
+func sign(key []byte, context, name, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(context))
+	mac.Write([]byte{0})
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// Sign returns the signature of a field value, the name is signed so that values cannot be swapped between fields
+func (k *Keyring) Sign(name, value string) string {
+	return k.SignContext("", name, value)
+}
+
+func (k *Keyring) Verify(name, value, signature string) bool {
+	return k.VerifyContext("", name, value, signature)
+}
+
+// SignContext binds the signature to a context such as a form ID or a record key,
+// a value signed in another context does not verify
+func (k *Keyring) SignContext(context, name, value string) string {
+	return base64.RawURLEncoding.EncodeToString(sign(k.keys[0], context, name, value))
+}
+
+func (k *Keyring) VerifyContext(context, name, value, signature string) bool {
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	for _, key := range k.keys {
+		if hmac.Equal(decoded, sign(key, context, name, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SignedHidden renders a hidden field along with the signature of its value,
+// a modified value is rejected during population and the element keeps its value
+func SignedHidden(name string, keyring *Keyring) *element {
+	e := Hidden(name).SetTemplate("signed-hidden.tmpl")
+	e.keyring = keyring
+	return e
+}
+
+// SetSignatureContext binds the signature to a context such as a record key,
+// so that a value signed for another record is rejected
+func (e *element) SetSignatureContext(context string) *element {
+	e.signatureContext = context
+	return e
+}
+
+func (e *element) IsSigned() bool {
+	return e.keyring != nil
+}
+
+func (e *element) SignatureName() string {
+	return e.Name() + SignatureFieldSuffix
+}
+
+func (e *element) Signature() string {
+	if e.keyring == nil {
+		return ""
+	}
+	return e.keyring.SignContext(e.signatureContext, e.Name(), e.Value())
+}
+
+func (e *element) setSignedValue(value, signature string) {
+	if !e.keyring.VerifyContext(e.signatureContext, e.Name(), value, signature) {
+		e.tampered = true
+		return
+	}
+	e.tampered = false
+	e.SetValue(value)
+}
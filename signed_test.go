@@ -0,0 +1,142 @@
+package goform
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestKeyring(t *testing.T) {
+	old := NewKeyring([]byte("old"))
+	rotated := NewKeyring([]byte("new"), []byte("old"))
+
+	signature := old.Sign("id", "42")
+
+	if !rotated.Verify("id", "42", signature) {
+		t.Error("expected values signed with a previous key to be accepted")
+	}
+	if rotated.Sign("id", "42") == signature {
+		t.Error("expected values to be signed with the current key")
+	}
+	if old.Verify("id", "43", signature) {
+		t.Error("expected a modified value to be rejected")
+	}
+	if old.Verify("price", "42", signature) {
+		t.Error("expected the signature of another field to be rejected")
+	}
+	if NewKeyring([]byte("new")).Verify("id", "42", signature) {
+		t.Error("expected a removed key to be rejected")
+	}
+	if old.Verify("id", "42", "%%%") {
+		t.Error("expected a malformed signature to be rejected")
+	}
+}
+
+func TestNewKeyring_EmptyKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  []byte
+		previous [][]byte
+	}{
+		{"nil current key", nil, nil},
+		{"empty current key", []byte{}, nil},
+		{"empty previous key", []byte("new"), [][]byte{{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != ErrMissingSigningKey {
+					t.Errorf("expected ErrMissingSigningKey, got %v", r)
+				}
+			}()
+			NewKeyring(tt.current, tt.previous...)
+		})
+	}
+}
+
+func TestSignedHidden(t *testing.T) {
+	keyring := NewKeyring([]byte("secret"))
+
+	t.Run("renders the signature along with the value", func(t *testing.T) {
+		el := SignedHidden("id", keyring).SetAttributes(Id("id"))
+		el.SetValue("42")
+
+		expected := `<input id="id" name="id" type="hidden" value="42">` +
+			`<input type="hidden" name="id_signature" value="` + keyring.Sign("id", "42") + `">`
+		if result := cleanHTML(el.Render()); result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, result)
+		}
+	})
+
+	t.Run("populates signed values", func(t *testing.T) {
+		el := SignedHidden("id", keyring)
+		f := Form().AddChildren(el)
+
+		values := url.Values{"id": {"42"}, "id_signature": {keyring.Sign("id", "42")}}
+		if err := f.PopulateFromRequest(postForm(values)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if el.Value() != "42" || !el.IsValid() {
+			t.Errorf("expected value=42, got %s", el.Value())
+		}
+	})
+
+	t.Run("rejects modified values", func(t *testing.T) {
+		tests := map[string]url.Values{
+			"modified value":    {"id": {"43"}, "id_signature": {keyring.Sign("id", "42")}},
+			"missing signature": {"id": {"43"}},
+		}
+
+		for name, values := range tests {
+			el := SignedHidden("id", keyring)
+			el.SetValue("42")
+			f := Form().AddChildren(el)
+
+			if err := f.PopulateFromRequest(postForm(values)); err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+
+			if el.Value() != "42" {
+				t.Errorf("%s: expected the value to be kept, got %s", name, el.Value())
+			}
+			if code := f.ValidationErrors()["id"]; code != ValidationInvalidSignature {
+				t.Errorf("%s: expected %s, got %s", name, ValidationInvalidSignature, code)
+			}
+			if !strings.Contains(string(el.Render()), keyring.Sign("id", "42")) {
+				t.Errorf("%s: expected the kept value to be signed", name)
+			}
+		}
+	})
+
+	t.Run("rejects values signed in another context", func(t *testing.T) {
+		// a cheap price signed on the page of another product
+		cheap := url.Values{"price": {"1"}, "price_signature": {keyring.SignContext("product-7", "price", "1")}}
+
+		price := SignedHidden("price", keyring).SetSignatureContext("product-42")
+		price.SetValue("990")
+		f := Form().AddChildren(price)
+
+		if err := f.PopulateFromRequest(postForm(cheap)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if price.Value() != "990" || price.ValidationError() != ValidationInvalidSignature {
+			t.Errorf("expected the price to be kept, got %s", price.Value())
+		}
+
+		signed := url.Values{"price": {"990"}, "price_signature": {keyring.SignContext("product-42", "price", "990")}}
+		if err := Form().AddChildren(price).PopulateFromRequest(postForm(signed)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !price.IsValid() {
+			t.Error("expected the value signed in the same context to be accepted")
+		}
+	})
+
+	t.Run("values signed without context do not verify in a context", func(t *testing.T) {
+		if keyring.VerifyContext("product-42", "price", "1", keyring.Sign("price", "1")) {
+			t.Error("expected the signature to be rejected")
+		}
+	})
+}
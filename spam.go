@@ -0,0 +1,119 @@
+package goform
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HoneypotFieldName  = "website_address"
+	TimestampFieldName = "form_timestamp"
+)
+
+var (
+	ErrSpamDetected   = errors.New("spam detected")
+	ErrMissingSpamKey = errors.New("spam protection key is missing")
+)
+
+// SpamError is reported when a submission looks automated, it is a form error rather than a field error
+type SpamError struct {
+	Reason string
+}
+
+func (e *SpamError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrSpamDetected, e.Reason)
+}
+
+func (e *SpamError) Unwrap() error {
+	return ErrSpamDetected
+}
+
+type spamProtection struct {
+	key         []byte
+	minFillTime time.Duration
+	maxAge      time.Duration
+}
+
+// now is replaced in tests
+var now = time.Now
+
+// WithSpamProtection renders a hidden honeypot field and a signed timestamp,
+// submissions filling the honeypot, sent less than minFillTime or more than maxAge after rendering are rejected.
+// A maxAge of zero or less never expires the timestamp. It panics when key is empty because anyone could forge the timestamp.
+func WithSpamProtection(key []byte, minFillTime, maxAge time.Duration) FormOption {
+	if len(key) == 0 {
+		panic(ErrMissingSpamKey)
+	}
+	return func(options *formOptions) {
+		options.spam = &spamProtection{
+			key:         key,
+			minFillTime: minFillTime,
+			maxAge:      maxAge,
+		}
+	}
+}
+
+func (p *spamProtection) sign(timestamp string) string {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(timestamp))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (p *spamProtection) timestamp() string {
+	timestamp := strconv.FormatInt(now().Unix(), 10)
+	return timestamp + "." + p.sign(timestamp)
+}
+
+func (p *spamProtection) verify(submitted url.Values) error {
+	if submitted.Get(HoneypotFieldName) != "" {
+		return &SpamError{Reason: "honeypot field is filled"}
+	}
+
+	timestamp, signature, ok := strings.Cut(submitted.Get(TimestampFieldName), ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(p.sign(timestamp))) {
+		return &SpamError{Reason: "timestamp is missing or tampered"}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return &SpamError{Reason: "timestamp is malformed"}
+	}
+	elapsed := now().Sub(time.Unix(seconds, 0))
+	if elapsed < p.minFillTime {
+		return &SpamError{Reason: "form was submitted too quickly"}
+	}
+	// a harvested timestamp cannot be replayed forever
+	if p.maxAge > 0 && elapsed > p.maxAge {
+		return &SpamError{Reason: "timestamp has expired"}
+	}
+
+	return nil
+}
+
+func (f *form) verifySpam(r *http.Request, submitted url.Values) error {
+	if f.options.spam == nil || isSafeMethod(r.Method) {
+		return nil
+	}
+	f.spam = f.options.spam.verify(submitted)
+	return f.spam
+}
+
+// syncHoneypotId scopes the honeypot ID to the form so that several protected forms can share a page
+func (f *form) syncHoneypotId() {
+	if f.honeypot != nil {
+		f.honeypot.SetAttributes(Id(f.Id() + "-" + HoneypotFieldName))
+	}
+}
+
+// SpamError returns the error recorded by PopulateFromRequest when the submission looks automated
+func (f *form) SpamError() error {
+	return f.spam
+}
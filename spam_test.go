@@ -0,0 +1,130 @@
+package goform
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForm_SpamProtection(t *testing.T) {
+	key := []byte("secret")
+	rendered := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newForm := func() *form {
+		now = func() time.Time { return rendered }
+		defer func() { now = time.Now }()
+
+		return Form(WithSpamProtection(key, 3*time.Second, time.Hour)).
+			SetAttributes(Id("contact")).
+			AddChildren(Text("message"))
+	}
+
+	submit := func(f *form, at time.Time, values url.Values) error {
+		now = func() time.Time { return at }
+		defer func() { now = time.Now }()
+
+		return f.PopulateFromRequest(postForm(values))
+	}
+
+	timestamp := newForm().timestamp.Value()
+
+	t.Run("renders the honeypot and the timestamp", func(t *testing.T) {
+		result := cleanHTML(newForm().Render())
+
+		honeypot := `<div hidden>` +
+			`<label for="contact-website_address">Leave this field empty</label>` +
+			`<input autocomplete="off" id="contact-website_address" name="website_address" tabindex="-1" type="text"></div>`
+		if !strings.Contains(result, honeypot) {
+			t.Errorf("expected the honeypot in %s", result)
+		}
+		if !strings.Contains(result, `<input name="form_timestamp" type="hidden" value="`+timestamp+`">`) {
+			t.Errorf("expected the timestamp in %s", result)
+		}
+	})
+
+	t.Run("accepts human submissions", func(t *testing.T) {
+		f := newForm()
+		err := submit(f, rendered.Add(10*time.Second), url.Values{
+			TimestampFieldName: {timestamp},
+			"message":          {"Hello"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if isValid, _ := f.IsValid(); !isValid || f.SpamError() != nil {
+			t.Error("expected the form to be valid")
+		}
+	})
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		values url.Values
+	}{
+		{"filled honeypot", rendered.Add(10 * time.Second), url.Values{TimestampFieldName: {timestamp}, HoneypotFieldName: {"http://spam"}}},
+		{"too fast", rendered.Add(time.Second), url.Values{TimestampFieldName: {timestamp}}},
+		{"expired timestamp", rendered.Add(2 * time.Hour), url.Values{TimestampFieldName: {timestamp}}},
+		{"missing timestamp", rendered.Add(10 * time.Second), url.Values{}},
+		{"tampered timestamp", rendered.Add(10 * time.Second), url.Values{TimestampFieldName: {"1." + strings.SplitN(timestamp, ".", 2)[1]}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newForm()
+			err := submit(f, tt.at, tt.values)
+
+			var spamErr *SpamError
+			if !errors.As(err, &spamErr) || !errors.Is(f.SpamError(), ErrSpamDetected) {
+				t.Errorf("expected a SpamError, got %v", err)
+			}
+
+			isValid, fieldErrors := f.IsValid()
+			if isValid {
+				t.Error("expected the form to be invalid")
+			}
+			if len(fieldErrors) != 0 {
+				t.Errorf("expected no field errors, got %v", fieldErrors)
+			}
+		})
+	}
+
+	t.Run("honeypot ids are scoped to the form", func(t *testing.T) {
+		first := Form(WithSpamProtection(key, 0, time.Hour)).SetAttributes(Id("newsletter"))
+		second := Form(WithSpamProtection(key, 0, time.Hour)).SetAttributes(Id("comment"))
+
+		if first.honeypot.Id() != "newsletter-website_address" || second.honeypot.Id() != "comment-website_address" {
+			t.Errorf("expected distinct honeypot ids, got %s and %s", first.honeypot.Id(), second.honeypot.Id())
+		}
+	})
+
+	t.Run("timestamps never expire without a maximum age", func(t *testing.T) {
+		now = func() time.Time { return rendered }
+		f := Form(WithSpamProtection(key, 0, 0)).AddChildren(Text("message"))
+		now = time.Now
+
+		err := submit(f, rendered.Add(30*24*time.Hour), url.Values{TimestampFieldName: {f.timestamp.Value()}})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects an empty key", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != ErrMissingSpamKey {
+				t.Errorf("expected ErrMissingSpamKey, got %v", r)
+			}
+		}()
+		WithSpamProtection(nil, 0, time.Hour)
+	})
+
+	t.Run("safe methods are not checked", func(t *testing.T) {
+		f := newForm()
+		if err := f.PopulateFromRequest(httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package goform
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// fieldState is the state of an element, the keys are short to keep encoded states compact
+type fieldState struct {
+	Values  []string `json:"v,omitempty"`
+	Invalid bool     `json:"i,omitempty"`
+	Error   string   `json:"e,omitempty"`
+}
+
+// formState is the state of a form, its fields are keyed by element path
+type formState struct {
+	Error  string                `json:"e,omitempty"`
+	Fields map[string]fieldState `json:"f,omitempty"`
+}
+
+// isSensitive reports whether the value of the element must not leave the server,
+// passwords are secrets and files cannot be restored into an input
+func (e *element) isSensitive() bool {
+	switch e.attributes.String("type") {
+	case InputTypePassword, InputTypeFile:
+		return true
+	}
+	return false
+}
+
+// WithSensitiveState includes the values of password and file elements in MarshalState and UnmarshalState,
+// flash states never include them
+func WithSensitiveState() FormOption {
+	return func(options *formOptions) {
+		options.sensitiveState = true
+	}
+}
+
+// statePath keys an element by the IDs of its enclosing fieldsets and groups followed by its name,
+// like the paths passed to ID generators, so that same-named elements in distinct containers are kept apart
+func statePath(parents []Renderer, e *element) string {
+	return strings.Join(append(containerPath(parents), e.Name()), "/")
+}
+
+// captureState returns the values and errors of the form, action elements are left out
+// and sensitive elements are only included when requested
+func (f *form) captureState(sensitive bool) formState {
+	state := formState{
+		Error:  f.error,
+		Fields: make(map[string]fieldState),
+	}
+
+	walk(f.children, nil, func(node Renderer, parents []Renderer) {
+		e, ok := node.(*element)
+		if !ok || e.isAction() || (e.isSensitive() && !sensitive) {
+			return
+		}
+		state.Fields[statePath(parents, e)] = fieldState{
+			Values:  slices.Clone(e.Values()),
+			Invalid: e.IsInvalid(),
+			Error:   e.error,
+		}
+	})
+
+	return state
+}
+
+// restoreState applies a captured state, the fields matching no element are ignored.
+// Like population, it keeps the server-side values of signed elements and, unless
+// WithLockedFieldsPopulation is set, of locked elements: a state is client-supplied data.
+func (f *form) restoreState(state formState, sensitive bool) {
+	walk(f.children, nil, func(node Renderer, parents []Renderer) {
+		e, ok := node.(*element)
+		if !ok || e.isAction() || e.IsSigned() || (e.isSensitive() && !sensitive) {
+			return
+		}
+		if !f.options.populateLocked && (e.isLocked() || inDisabledFieldSet(parents)) {
+			return
+		}
+		field, ok := state.Fields[statePath(parents, e)]
+		if !ok {
+			return
+		}
+
+		if e.IsMultiValued() {
+			e.SetValues(field.Values...)
+		} else if len(field.Values) > 0 {
+			e.SetValue(field.Values[0])
+		} else {
+			e.SetValue("")
+		}
+
+		e.SetError(field.Error)
+		if field.Invalid {
+			e.MarkAsInvalid()
+		} else {
+			e.attributes.Unset("aria-invalid")
+		}
+	})
+
+	f.SetError(state.Error)
+}
+
+// MarshalState encodes the values, validity and errors of the elements and the form error as JSON,
+// the elements are keyed by path, e.g. shipping/city for city in a fieldset with the ID shipping,
+// and password and file elements are left out unless WithSensitiveState is set
+func (f *form) MarshalState() ([]byte, error) {
+	return json.Marshal(f.captureState(f.options.sensitiveState))
+}
+
+// UnmarshalState applies a state encoded by MarshalState, the elements missing from the state are left untouched
+// and so are signed and locked elements
+func (f *form) UnmarshalState(data []byte) error {
+	var state formState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to decode form state: %w", err)
+	}
+
+	f.restoreState(state, f.options.sensitiveState)
+	return nil
+}
@@ -0,0 +1,193 @@
+package goform
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestForm_MarshalState(t *testing.T) {
+	newForm := func(options ...FormOption) *form {
+		return Form(options...).AddChildren(
+			FieldSet("Address",
+				Text("address.city").SetAttributes(Attr("required", true)),
+				Text("address.street"),
+			),
+			Password("password"),
+			File("avatar"),
+			Submit("save"),
+		)
+	}
+
+	f := newForm()
+	if err := f.PopulateFromValues(url.Values{
+		"address.city":   {""},
+		"address.street": {"1 Main Street"},
+		"password":       {"secret"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.Elements()["address.city"].(*element).SetError("Enter a city")
+	f.SetError("The draft is incomplete")
+
+	data, err := f.MarshalState()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"e":"The draft is incomplete","f":{"address.city":{"i":true,"e":"Enter a city"},"address.street":{"v":["1 Main Street"]}}}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	t.Run("restores the state into the same form definition", func(t *testing.T) {
+		restored := newForm()
+		if err := restored.UnmarshalState(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		elements := restored.Elements()
+		city := elements["address.city"].(*element)
+		if city.Error() != "Enter a city" || !city.IsInvalid() {
+			t.Errorf("expected the city error to be restored, got %q", city.Error())
+		}
+		if street := elements["address.street"]; street.Value() != "1 Main Street" || street.(*element).IsInvalid() {
+			t.Errorf("expected a valid street, got %q", street.Value())
+		}
+		if restored.Error() != "The draft is incomplete" {
+			t.Errorf("expected the form error to be restored, got %q", restored.Error())
+		}
+	})
+
+	t.Run("clears the validity of valid elements", func(t *testing.T) {
+		restored := newForm()
+		restored.Elements()["address.street"].MarkAsInvalid()
+
+		if err := restored.UnmarshalState(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if restored.Elements()["address.street"].(*element).IsInvalid() {
+			t.Error("expected the street to be valid")
+		}
+	})
+
+	t.Run("includes sensitive elements on demand", func(t *testing.T) {
+		f := newForm(WithSensitiveState())
+		if err := f.PopulateFromValues(url.Values{"password": {"secret"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := f.MarshalState()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		restored := newForm(WithSensitiveState())
+		if err := restored.UnmarshalState(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if password := restored.Elements()["password"].Value(); password != "secret" {
+			t.Errorf("expected the password to be restored, got %q", password)
+		}
+	})
+
+	t.Run("ignores sensitive elements by default", func(t *testing.T) {
+		restored := newForm()
+		if err := restored.UnmarshalState([]byte(`{"f":{"password":{"v":["secret"]}}}`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if password := restored.Elements()["password"].Value(); password != "" {
+			t.Errorf("expected the password to be ignored, got %q", password)
+		}
+	})
+
+	t.Run("keeps the value of signed elements", func(t *testing.T) {
+		price := SignedHidden("price", NewKeyring([]byte("key")))
+		price.SetValue("100")
+		restored := Form().AddChildren(price)
+
+		if err := restored.UnmarshalState([]byte(`{"f":{"price":{"v":["1"]}}}`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if price.Value() != "100" {
+			t.Errorf("expected the signed price to be kept, got %q", price.Value())
+		}
+	})
+
+	t.Run("keeps the value of locked elements", func(t *testing.T) {
+		state := []byte(`{"f":{"role":{"v":["admin"]},"plan":{"v":["pro"]}}}`)
+		newLockedForm := func(options ...FormOption) *form {
+			role := Text("role").SetAttributes(Attr("readonly", true))
+			role.SetValue("member")
+			plan := Text("plan")
+			plan.SetValue("free")
+			return Form(options...).AddChildren(role, FieldSet("Plan", plan).SetAttributes(Attr("disabled", true)))
+		}
+
+		restored := newLockedForm()
+		if err := restored.UnmarshalState(state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		elements := restored.Elements()
+		if role, plan := elements["role"].Value(), elements["plan"].Value(); role != "member" || plan != "free" {
+			t.Errorf("expected the locked values to be kept, got %q %q", role, plan)
+		}
+
+		restored = newLockedForm(WithLockedFieldsPopulation())
+		if err := restored.UnmarshalState(state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		elements = restored.Elements()
+		if role, plan := elements["role"].Value(), elements["plan"].Value(); role != "admin" || plan != "pro" {
+			t.Errorf("expected the locked values to be restored, got %q %q", role, plan)
+		}
+	})
+
+	t.Run("keys same-named elements by their container path", func(t *testing.T) {
+		newAddressForm := func() *form {
+			return Form().AddChildren(
+				FieldSet("Billing", Text("city")).SetAttributes(Id("billing")),
+				FieldSet("Shipping", Group(Text("city")).SetAttributes(Id("address"))).SetAttributes(Id("shipping")),
+			)
+		}
+		cities := func(f *form) []string {
+			var values []string
+			walk(f.children, nil, func(node Renderer, _ []Renderer) {
+				if e, ok := node.(*element); ok {
+					values = append(values, e.Value())
+				}
+			})
+			return values
+		}
+
+		f := newAddressForm()
+		walk(f.children, nil, func(node Renderer, parents []Renderer) {
+			if e, ok := node.(*element); ok {
+				e.SetValue(map[int]string{1: "Paris", 2: "Lyon"}[len(parents)])
+			}
+		})
+
+		data, err := f.MarshalState()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `{"f":{"billing/city":{"v":["Paris"]},"shipping/address/city":{"v":["Lyon"]}}}`
+		if string(data) != expected {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+
+		restored := newAddressForm()
+		if err := restored.UnmarshalState(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if values := cities(restored); strings.Join(values, ",") != "Paris,Lyon" {
+			t.Errorf("expected both cities to be restored, got %v", values)
+		}
+	})
+
+	t.Run("rejects malformed states", func(t *testing.T) {
+		if err := newForm().UnmarshalState([]byte(`{`)); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package goform
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrUnknownFields = errors.New("unknown fields")
+	ErrTooManyFields = errors.New("too many fields")
+	ErrTooManyValues = errors.New("too many values")
+)
+
+// UnknownFieldsError lists the request fields and file parts that match no element
+type UnknownFieldsError struct {
+	Fields []string
+	Files  []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	names := append(slices.Clone(e.Fields), e.Files...)
+	return fmt.Sprintf("%s: %s", ErrUnknownFields, strings.Join(names, ", "))
+}
+
+func (e *UnknownFieldsError) Unwrap() error {
+	return ErrUnknownFields
+}
+
+// WithStrictFields rejects requests containing fields or file parts that match no element
+func WithStrictFields() FormOption {
+	return func(options *formOptions) {
+		options.strict = true
+	}
+}
+
+// WithMaxFields limits the number of fields and file parts of a request.
+// The fields are counted once the body is parsed, WithMaxBodySize bounds the cost of parsing a flooding request.
+func WithMaxFields(limit int) FormOption {
+	return func(options *formOptions) {
+		options.maxFields = limit
+	}
+}
+
+// WithMaxValuesPerField limits the number of values submitted for a single field.
+// The values are counted once the body is parsed, WithMaxBodySize bounds the cost of parsing a flooding request.
+func WithMaxValuesPerField(limit int) FormOption {
+	return func(options *formOptions) {
+		options.maxValuesPerField = limit
+	}
+}
+
+// knownFields returns the names of the fields the form renders, including the injected ones
+func (f *form) knownFields() map[string]bool {
+	known := make(map[string]bool)
+
+	walk(f.children, nil, func(node Renderer, _ []Renderer) {
+		if e, ok := node.(Element); ok {
+			known[e.Name()] = true
+		}
+		if s, ok := node.(signedValuer); ok && s.IsSigned() {
+			known[s.SignatureName()] = true
+		}
+	})
+
+	for _, field := range f.SecurityFields() {
+		if e, ok := field.(Element); ok {
+			known[e.Name()] = true
+		}
+	}
+
+	return known
+}
+
+func (f *form) fileFields() map[string]bool {
+	files := make(map[string]bool)
+
+	walk(f.children, nil, func(node Renderer, _ []Renderer) {
+		if e, ok := node.(*element); ok && e.attributes.String("type") == InputTypeFile {
+			files[e.Name()] = true
+		}
+	})
+
+	return files
+}
+
+func (f *form) verifyFields(fields url.Values, fileHeaders map[string][]*multipart.FileHeader) error {
+	files := make(map[string]int, len(fileHeaders))
+	for name, headers := range fileHeaders {
+		files[name] = len(headers)
+	}
+
+	if limit := f.options.maxFields; limit > 0 && len(fields)+len(files) > limit {
+		return fmt.Errorf("%w: %d fields, at most %d are allowed", ErrTooManyFields, len(fields)+len(files), limit)
+	}
+
+	if limit := f.options.maxValuesPerField; limit > 0 {
+		for name, values := range fields {
+			if len(values) > limit {
+				return fmt.Errorf("%w: field %s has %d values, at most %d are allowed", ErrTooManyValues, name, len(values), limit)
+			}
+		}
+		for name, count := range files {
+			if count > limit {
+				return fmt.Errorf("%w: field %s has %d files, at most %d are allowed", ErrTooManyValues, name, count, limit)
+			}
+		}
+	}
+
+	if !f.options.strict {
+		return nil
+	}
+
+	known := f.knownFields()
+	fileFields := f.fileFields()
+	unknown := &UnknownFieldsError{}
+
+	for name := range fields {
+		if !known[name] {
+			unknown.Fields = append(unknown.Fields, name)
+		}
+	}
+	for name := range files {
+		if !fileFields[name] {
+			unknown.Files = append(unknown.Files, name)
+		}
+	}
+
+	if len(unknown.Fields) == 0 && len(unknown.Files) == 0 {
+		return nil
+	}
+
+	slices.Sort(unknown.Fields)
+	slices.Sort(unknown.Files)
+	return unknown
+}
@@ -0,0 +1,141 @@
+package goform
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestForm_StrictFields(t *testing.T) {
+	keyring := NewKeyring([]byte("secret"))
+
+	newForm := func(options ...FormOption) *form {
+		return Form(options...).AddChildren(
+			Text("name"),
+			File("avatar"),
+			SignedHidden("id", keyring),
+		)
+	}
+
+	t.Run("accepts known fields", func(t *testing.T) {
+		values := url.Values{"name": {"Jane"}, "id": {"1"}, "id_signature": {keyring.Sign("id", "1")}}
+
+		if err := newForm(WithStrictFields()).PopulateFromRequest(postForm(values)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects unknown fields and file parts", func(t *testing.T) {
+		values := url.Values{"name": {"Jane"}, "role": {"admin"}, "is_admin": {"1"}}
+		req := &http.Request{
+			Method:   http.MethodPost,
+			Form:     values,
+			PostForm: values,
+			MultipartForm: &multipart.Form{
+				File: map[string][]*multipart.FileHeader{
+					"avatar":  {{Filename: "me.png"}},
+					"payload": {{Filename: "evil.sh"}},
+					"name":    {{Filename: "name.txt"}},
+				},
+			},
+		}
+
+		f := newForm(WithStrictFields())
+		err := f.PopulateFromRequest(req)
+
+		var unknown *UnknownFieldsError
+		if !errors.As(err, &unknown) || !errors.Is(err, ErrUnknownFields) {
+			t.Fatalf("expected an UnknownFieldsError, got %v", err)
+		}
+		if !slices.Equal(unknown.Fields, []string{"is_admin", "role"}) {
+			t.Errorf("expected is_admin and role, got %v", unknown.Fields)
+		}
+		if !slices.Equal(unknown.Files, []string{"name", "payload"}) {
+			t.Errorf("expected name and payload, got %v", unknown.Files)
+		}
+		if f.Error() != err.Error() {
+			t.Errorf("expected a form error, got %s", f.Error())
+		}
+		if f.Elements()["name"].Value() != "" {
+			t.Error("expected the form not to be populated")
+		}
+	})
+
+	t.Run("ignores unknown fields by default", func(t *testing.T) {
+		values := url.Values{"name": {"Jane"}, "role": {"admin"}}
+
+		if err := newForm().PopulateFromRequest(postForm(values)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("query parameters are not form fields", func(t *testing.T) {
+		req := postForm(url.Values{"name": {"Jane"}})
+		req.URL.RawQuery = "page=2"
+
+		if err := newForm(WithStrictFields()).PopulateFromRequest(req); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("accepts the injected fields", func(t *testing.T) {
+		store := &CookieTokenStore{}
+		f := Form(WithStrictFields(), WithCSRF(store)).AddChildren(Text("name"))
+
+		cookie := &http.Cookie{Name: CSRFCookieName, Value: "token"}
+		req := postForm(url.Values{"name": {"Jane"}, CSRFFieldName: {"token"}}, cookie)
+
+		if err := f.PopulateFromRequest(req); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestForm_FieldLimits(t *testing.T) {
+	t.Run("limits the number of fields", func(t *testing.T) {
+		values := url.Values{"a": {"1"}, "b": {"2"}, "c": {"3"}}
+		f := Form(WithMaxFields(2))
+
+		if err := f.PopulateFromRequest(postForm(values)); !errors.Is(err, ErrTooManyFields) {
+			t.Errorf("expected ErrTooManyFields, got %v", err)
+		}
+	})
+
+	t.Run("limits the number of values per field", func(t *testing.T) {
+		values := url.Values{"tags": {"a", "b", "c"}}
+		f := Form(WithMaxValuesPerField(2)).AddChildren(Select("tags").SetAttributes(Attr("multiple", true)))
+
+		if err := f.PopulateFromRequest(postForm(values)); !errors.Is(err, ErrTooManyValues) {
+			t.Errorf("expected ErrTooManyValues, got %v", err)
+		}
+	})
+
+	t.Run("never binds the query string of a submission", func(t *testing.T) {
+		req := postForm(url.Values{"name": {"Jane"}})
+		req.URL.RawQuery = "role=admin&role=x&evil=1"
+
+		f := Form(WithStrictFields(), WithMaxValuesPerField(1)).AddChildren(Text("name"), Text("role"))
+		if err := f.PopulateFromRequest(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		elements := f.Elements()
+		if elements["role"].Value() != "" || elements["name"].Value() != "Jane" {
+			t.Errorf("expected only the body to be bound, got role=%q", elements["role"].Value())
+		}
+	})
+
+	t.Run("accepts requests within the limits", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Form = url.Values{"a": {"1"}, "b": {"1", "2"}}
+		req.PostForm = req.Form
+
+		if err := Form(WithMaxFields(2), WithMaxValuesPerField(2)).PopulateFromRequest(req); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -1,24 +1,77 @@
 package goform
 
 import (
+	"bytes"
 	"embed"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"io/fs"
+	"maps"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrTemplateNotFound         = errors.New("template was not found")
+	ErrTemplatesAlreadyLoaded   = errors.New("templates were already loaded")
+	ErrUnsupportedAttributeType = errors.New("unsupported attribute data type")
+	ErrReservedTemplateFunc     = errors.New("template function name is reserved")
 )
 
 //go:embed templates
 var templateFS embed.FS
 var templatesOptions *templatesOverrideOptions
+var templatesLoaded atomic.Bool
+var templateFuncs = make(template.FuncMap)
+
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer) //nolint:errcheck,forcetypeassert // the pool only holds buffers
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
 
 type templatesOverrideOptions struct {
 	filesystem fs.FS
 	patterns   []string
 }
 
+func (o *templatesOverrideOptions) validate() error {
+	if len(o.patterns) == 0 {
+		return fmt.Errorf("%w: no overriding template patterns were provided", ErrTemplateNotFound)
+	}
+
+	for _, pattern := range o.patterns {
+		matches, err := fs.Glob(o.filesystem, pattern)
+		if err != nil {
+			return fmt.Errorf("invalid template pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("%w: pattern %q matches no files", ErrTemplateNotFound, pattern)
+		}
+	}
+
+	return nil
+}
+
 func SetOverridingTemplates(filesystem fs.FS, patterns ...string) {
 	templatesOptions = &templatesOverrideOptions{
 		filesystem: filesystem,
@@ -26,75 +79,139 @@ func SetOverridingTemplates(filesystem fs.FS, patterns ...string) {
 	}
 }
 
-type templateRenderer struct {
-	base      *template.Template
-	overwrite *template.Template
+// SetStrictOverridingTemplates registers overriding templates and parses them immediately,
+// returning an error when a pattern matches no files or a template is malformed.
+func SetStrictOverridingTemplates(filesystem fs.FS, patterns ...string) error {
+	if templatesLoaded.Load() {
+		return ErrTemplatesAlreadyLoaded
+	}
+
+	options := &templatesOverrideOptions{
+		filesystem: filesystem,
+		patterns:   patterns,
+	}
+
+	if err := options.validate(); err != nil {
+		return err
+	}
+
+	if _, err := newTemplateRenderer(options, defaultTheme); err != nil {
+		return err
+	}
+
+	templatesOptions = options
+
+	return nil
 }
 
-func (tr *templateRenderer) Render(name string, data any) template.HTML {
-	if tr.overwrite != nil {
-		if t := tr.overwrite.Lookup(name); t != nil {
-			return tr.render(t, data)
+// SetTemplateFuncs registers extra functions available in built-in and overriding templates,
+// it must be called before the templates are loaded
+func SetTemplateFuncs(funcs template.FuncMap) error {
+	builtins := builtinTemplateFuncs()
+	for name := range funcs {
+		if _, ok := builtins[name]; ok {
+			return fmt.Errorf("%w: %s", ErrReservedTemplateFunc, name)
 		}
 	}
 
-	if tr.base != nil {
-		if t := tr.base.Lookup(name); t != nil {
-			return tr.render(t, data)
-		}
+	if templatesLoaded.Load() {
+		return ErrTemplatesAlreadyLoaded
 	}
 
-	//nolint:gosec // G203
-	return template.HTML(fmt.Sprintf("template %s was not found", name))
+	maps.Copy(templateFuncs, funcs)
+
+	return nil
 }
 
-func (tr *templateRenderer) render(t *template.Template, data any) template.HTML {
-	var buf strings.Builder
-	if err := t.Execute(&buf, data); err != nil {
-		return template.HTML(fmt.Sprintf("%s", err)) //nolint:gosec // G203
+func builtinTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"form_attributes": attributesRenderer(),
+		"form_component":  componentRenderer(),
+		"form_class":      classModifier(),
+		"form_attr":       attributeModifier(),
 	}
-	return template.HTML(buf.String()) //nolint:gosec // G203
 }
 
-var getTemplateRenderer = sync.OnceValue(
-	func() *templateRenderer {
-		fn := template.FuncMap{
-			"form_attributes": attributesRenderer(),
-			"form_component":  componentRenderer(),
+type templateRenderer struct {
+	templates *template.Template
+}
+
+func (tr *templateRenderer) lookup(name string) (*template.Template, error) {
+	if tr.templates != nil {
+		if t := tr.templates.Lookup(name); t != nil {
+			return t, nil
 		}
+	}
 
-		t, err := template.New("base").
-			Funcs(fn).
-			ParseFS(templateFS, "templates/*.tmpl")
+	return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+}
 
-		if err != nil {
-			panic(err)
-		}
+func (tr *templateRenderer) Render(name string, data any) template.HTML {
+	h, err := tr.RenderE(name, data)
+	if err != nil {
+		return renderErrorHTML(err)
+	}
+	return h
+}
 
-		r := &templateRenderer{
-			base: t,
-		}
+func (tr *templateRenderer) RenderE(name string, data any) (template.HTML, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
 
-		if templatesOptions != nil {
-			t, err := template.New("override").
-				Funcs(fn).
-				ParseFS(
-					templatesOptions.filesystem,
-					templatesOptions.patterns...,
-				)
+	if err := tr.RenderTo(buf, name, data); err != nil {
+		return template.HTML(""), err
+	}
+	return template.HTML(buf.String()), nil //nolint:gosec // G203
+}
 
-			if err != nil {
-				panic(err)
-			}
+func (tr *templateRenderer) RenderTo(w io.Writer, name string, data any) error {
+	t, err := tr.lookup(name)
+	if err != nil {
+		return err
+	}
+	return tr.render(w, t, data)
+}
 
-			r.overwrite = t
+func (tr *templateRenderer) render(w io.Writer, t *template.Template, data any) error {
+	if err := t.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render template %s: %w", t.Name(), err)
+	}
+	return nil
+}
+
+func newTemplateRenderer(options *templatesOverrideOptions, theme Theme) (*templateRenderer, error) {
+	fn := maps.Clone(templateFuncs)
+	maps.Copy(fn, builtinTemplateFuncs())
+
+	t, err := template.New("base").
+		Funcs(fn).
+		ParseFS(templateFS, "templates/*.tmpl")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base templates: %w", err)
+	}
+
+	if theme != ThemeDefault {
+		if _, err := t.ParseFS(templateFS, theme.pattern()); err != nil {
+			return nil, fmt.Errorf("failed to parse %s theme templates: %w", theme, err)
 		}
+	}
 
-		return r
-	})
+	if options != nil {
+		if _, err := t.ParseFS(options.filesystem, options.patterns...); err != nil {
+			return nil, fmt.Errorf("failed to parse overriding templates: %w", err)
+		}
+	}
+
+	return &templateRenderer{
+		templates: t,
+	}, nil
+}
 
 type Renderer interface {
 	Render() template.HTML
+	RenderE() (template.HTML, error)
+	RenderTo(w io.Writer) error
 }
 
 type ErrorRenderer interface {
@@ -107,15 +224,58 @@ type HintRenderer interface {
 
 type TemplateRenderer interface {
 	Render(name string, data any) template.HTML
+	RenderE(name string, data any) (template.HTML, error)
+	RenderTo(w io.Writer, name string, data any) error
 }
 
-func FormRenderer() func(f Renderer) template.HTML {
+func FormRenderer() func(f Renderer) (template.HTML, error) {
 	return componentRenderer()
 }
 
-func componentRenderer() func(f Renderer) template.HTML {
-	return func(f Renderer) template.HTML {
-		return f.Render()
+func componentRenderer() func(f Renderer) (template.HTML, error) {
+	return func(f Renderer) (template.HTML, error) {
+		return f.RenderE()
+	}
+}
+
+// renderChild writes a child straight into the writer its container is rendered to,
+// the template output is written to the same writer so the child lands in place
+func renderChild(w io.Writer, child Renderer) (template.HTML, error) {
+	return template.HTML(""), child.RenderTo(w)
+}
+
+// renderContainer renders a container through its RenderTo so that its children are streamed into the same buffer
+func renderContainer(c Renderer) (template.HTML, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := c.RenderTo(buf); err != nil {
+		return template.HTML(""), err
+	}
+	return template.HTML(buf.String()), nil //nolint:gosec // G203
+}
+
+func renderErrorHTML(err error) template.HTML {
+	return template.HTML(template.HTMLEscapeString(err.Error())) //nolint:gosec // G203
+}
+
+func classModifier() func(Attrs, ...string) Attrs {
+	return func(attributes Attrs, classes ...string) Attrs {
+		a := maps.Clone(attributes)
+		if a == nil {
+			a = make(Attrs)
+		}
+		return a.AddTokens("class", classes...)
+	}
+}
+
+func attributeModifier() func(Attrs, string, any) Attrs {
+	return func(attributes Attrs, name string, value any) Attrs {
+		a := maps.Clone(attributes)
+		if a == nil {
+			a = make(Attrs)
+		}
+		return a.Set(name, value)
 	}
 }
 
@@ -135,18 +295,30 @@ func attributesRenderer() func(map[string]any) (template.HTMLAttr, error) {
 		sort.Strings(keys)
 
 		for _, name := range keys {
+			// event handlers are bound by the script of the form
+			if isEventHandler(name) {
+				continue
+			}
+
 			value := attributes[name]
 			switch value := value.(type) {
 			case bool:
 				if value {
-					fmt.Fprintf(&s, "%s ", template.HTMLEscapeString(name))
+					s.WriteString(template.HTMLEscapeString(name))
+					s.WriteByte(' ')
 				}
 			case string:
 				if value != "" {
-					fmt.Fprintf(&s, `%s="%s" `, template.HTMLEscapeString(name), template.HTMLEscapeString(value))
+					s.WriteString(template.HTMLEscapeString(name))
+					s.WriteString(`="`)
+					s.WriteString(template.HTMLEscapeString(value))
+					s.WriteString(`" `)
 				}
 			default:
-				fmt.Fprintf(&s, `attribute %s has an unsupported data type %T, only boolean & string are allowed`, name, value)
+				return template.HTMLAttr(""), fmt.Errorf(
+					"%w: attribute %s has type %T, only boolean & string are allowed",
+					ErrUnsupportedAttributeType, name, value,
+				)
 			}
 		}
 		//nolint:gosec // G203
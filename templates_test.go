@@ -1,9 +1,12 @@
 package goform
 
 import (
+	"errors"
 	"html/template"
+	"io"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 // Mock renderer for testing
@@ -15,30 +18,388 @@ func (m *mockRenderer) Render() template.HTML {
 	return template.HTML(m.html) //nolint:gosec // G203
 }
 
+func (m *mockRenderer) RenderE() (template.HTML, error) {
+	return m.Render(), nil
+}
+
+func (m *mockRenderer) RenderTo(w io.Writer) error {
+	_, err := io.WriteString(w, m.html)
+	return err
+}
+
 func TestTemplateRenderer_render(t *testing.T) {
 	t.Run("executes template successfully", func(t *testing.T) {
 		tmpl := template.Must(template.New("test").Parse("<div>{{.}}</div>"))
 		tr := &templateRenderer{}
 
-		result := tr.render(tmpl, "hello")
-		expected := template.HTML("<div>hello</div>")
+		var buf strings.Builder
+		if err := tr.render(&buf, tmpl, "hello"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
-		if result != expected {
-			t.Errorf("expected %s, got %s", expected, result)
+		expected := "<div>hello</div>"
+		if buf.String() != expected {
+			t.Errorf("expected %s, got %s", expected, buf.String())
 		}
 	})
 
-	t.Run("returns error message on execution failure", func(t *testing.T) {
+	t.Run("returns error on execution failure", func(t *testing.T) {
 		// Template that will cause an error (accessing non-existent field)
 		tmpl := template.Must(template.New("test").Parse("<div>{{.NonExistent.Field}}</div>"))
 		tr := &templateRenderer{}
 
-		result := tr.render(tmpl, "hello")
-		resultStr := string(result)
+		var buf strings.Builder
+		err := tr.render(&buf, tmpl, "hello")
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), "can't evaluate field NonExistent") {
+			t.Errorf("expected error message about NonExistent field, got %s", err)
+		}
+	})
+}
+
+func TestTemplateRenderer_RenderE(t *testing.T) {
+	t.Run("renders existing template", func(t *testing.T) {
+		tr := &templateRenderer{
+			templates: template.Must(template.New("test.tmpl").Parse("<p>{{.}}</p>")),
+		}
+
+		result, err := tr.RenderE("test.tmpl", "hello")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != template.HTML("<p>hello</p>") {
+			t.Errorf("expected <p>hello</p>, got %s", result)
+		}
+	})
+
+	t.Run("returns an error for a missing template", func(t *testing.T) {
+		tr := &templateRenderer{}
+
+		result, err := tr.RenderE("missing.tmpl", nil)
+		if !errors.Is(err, ErrTemplateNotFound) {
+			t.Errorf("expected ErrTemplateNotFound, got %v", err)
+		}
+		if result != template.HTML("") {
+			t.Errorf("expected empty output, got %s", result)
+		}
+	})
+
+	t.Run("returns execution errors", func(t *testing.T) {
+		tr := &templateRenderer{
+			templates: template.Must(template.New("test.tmpl").Parse("{{.NonExistent}}")),
+		}
+
+		if _, err := tr.RenderE("test.tmpl", "hello"); err == nil {
+			t.Error("expected an execution error")
+		}
+	})
+}
+
+func TestTemplateRenderer_RenderTo(t *testing.T) {
+	t.Run("writes to the writer", func(t *testing.T) {
+		tr := &templateRenderer{
+			templates: template.Must(template.New("test.tmpl").Parse("<p>{{.}}</p>")),
+		}
+
+		var buf strings.Builder
+		if err := tr.RenderTo(&buf, "test.tmpl", "hello"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "<p>hello</p>" {
+			t.Errorf("expected <p>hello</p>, got %s", buf.String())
+		}
+	})
+
+	t.Run("returns an error for a missing template", func(t *testing.T) {
+		tr := &templateRenderer{}
+
+		var buf strings.Builder
+		err := tr.RenderTo(&buf, "missing.tmpl", nil)
+		if !errors.Is(err, ErrTemplateNotFound) {
+			t.Errorf("expected ErrTemplateNotFound, got %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("expected nothing to be written, got %s", buf.String())
+		}
+	})
+}
+
+func TestTemplateRenderer_Render(t *testing.T) {
+	t.Run("embeds escaped error message", func(t *testing.T) {
+		tr := &templateRenderer{}
+
+		result := string(tr.Render("<missing>.tmpl", nil))
+		if !strings.Contains(result, "template was not found: &lt;missing&gt;.tmpl") {
+			t.Errorf("expected escaped error message, got %s", result)
+		}
+	})
+}
+
+func TestComponent_RenderE(t *testing.T) {
+	t.Run("propagates child errors", func(t *testing.T) {
+		f := Form().AddChildren(
+			Text("username").SetAttributes(Attr("id", "username")),
+		)
+		f.Attributes()["data-broken"] = 42
+
+		if _, err := f.RenderE(); !errors.Is(err, ErrUnsupportedAttributeType) {
+			t.Errorf("expected ErrUnsupportedAttributeType, got %v", err)
+		}
+	})
+
+	t.Run("propagates nested errors", func(t *testing.T) {
+		el := Text("username")
+		el.Attributes()["data-broken"] = 42
+		f := Form().AddChildren(FieldSet("Login", Group(el)))
+
+		if _, err := f.RenderE(); !errors.Is(err, ErrUnsupportedAttributeType) {
+			t.Errorf("expected ErrUnsupportedAttributeType, got %v", err)
+		}
+
+		var buf strings.Builder
+		if err := f.RenderTo(&buf); !errors.Is(err, ErrUnsupportedAttributeType) {
+			t.Errorf("expected ErrUnsupportedAttributeType, got %v", err)
+		}
+	})
+
+	t.Run("renders the same output as Render", func(t *testing.T) {
+		f := Form().AddChildren(
+			Text("username").SetLabel("Username"),
+			FieldSet("Details", Email("email")),
+		)
+
+		result, err := f.RenderE()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != f.Render() {
+			t.Errorf("expected RenderE to match Render, got %s", result)
+		}
+
+		var buf strings.Builder
+		if err := f.RenderTo(&buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if template.HTML(buf.String()) != result {
+			t.Errorf("expected RenderTo to match RenderE, got %s", buf.String())
+		}
+	})
+}
+
+func TestNewTemplateRenderer(t *testing.T) {
+	render := func(t *testing.T, r *templateRenderer, name string, data any) string {
+		t.Helper()
+
+		result, err := r.RenderE(name, data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return cleanHTML(result)
+	}
+
+	t.Run("overrides whole templates", func(t *testing.T) {
+		r, err := newTemplateRenderer(&templatesOverrideOptions{
+			filesystem: fstest.MapFS{
+				"hint.tmpl": &fstest.MapFile{Data: []byte(`<em>{{ .Hint }}</em>`)},
+			},
+			patterns: []string{"*.tmpl"},
+		}, ThemeDefault)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result := render(t, r, "hint.tmpl", struct{ Id, Hint string }{"id", "hint"})
+		if result != "<em>hint</em>" {
+			t.Errorf("expected overriding template to be used, got %s", result)
+		}
+	})
+
+	t.Run("overrides a single block", func(t *testing.T) {
+		r, err := newTemplateRenderer(&templatesOverrideOptions{
+			filesystem: fstest.MapFS{
+				"input.tmpl": &fstest.MapFile{Data: []byte(`{{ define "input.wrapper" }}<p class="field">{{ template "input.control" . }}</p>{{ end }}`)},
+			},
+			patterns: []string{"*.tmpl"},
+		}, ThemeDefault)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		el := Text("username").SetAttributes(Id("username"))
+		result := render(t, r, "input.tmpl", el)
+		if result != `<p class="field"><input id="username" name="username" type="text"></p>` {
+			t.Errorf("expected wrapper block to be overridden, got %s", result)
+		}
+
+		result = render(t, r, "input.tmpl", Submit("save").SetAttributes(Id("save")))
+		if result != `<input id="save" name="save" type="submit">` {
+			t.Errorf("expected button block to fall back to the default, got %s", result)
+		}
+	})
+
+	t.Run("layers overrides on top of themes", func(t *testing.T) {
+		r, err := newTemplateRenderer(&templatesOverrideOptions{
+			filesystem: fstest.MapFS{
+				"blocks.tmpl": &fstest.MapFile{Data: []byte(`{{ define "input.label" }}<b>{{ .Label }}</b>{{ end }}`)},
+			},
+			patterns: []string{"*.tmpl"},
+		}, ThemeBootstrap5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		el := Text("username").SetAttributes(Id("username")).SetLabel("Username")
+		result := render(t, r, "input.tmpl", el)
+		expected := `<div class="mb-3"><b>Username</b><input class="form-control" id="username" name="username" type="text"></div>`
+		if result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, result)
+		}
+	})
+}
+
+func TestSetTemplateFuncs(t *testing.T) {
+	t.Run("rejects built-in names", func(t *testing.T) {
+		err := SetTemplateFuncs(template.FuncMap{
+			"form_component": func() string { return "" },
+		})
+		if !errors.Is(err, ErrReservedTemplateFunc) {
+			t.Errorf("expected ErrReservedTemplateFunc, got %v", err)
+		}
+	})
+
+	t.Run("fails once templates are loaded", func(t *testing.T) {
+		getTemplateRenderer()
+
+		err := SetTemplateFuncs(template.FuncMap{
+			"t": func(s string) string { return s },
+		})
+		if !errors.Is(err, ErrTemplatesAlreadyLoaded) {
+			t.Errorf("expected ErrTemplatesAlreadyLoaded, got %v", err)
+		}
+	})
+
+	t.Run("exposes functions to overriding templates", func(t *testing.T) {
+		previous := templateFuncs
+		t.Cleanup(func() {
+			templateFuncs = previous
+		})
+
+		templateFuncs = template.FuncMap{
+			"t": func(s string) string { return strings.ToUpper(s) },
+		}
+
+		r, err := newTemplateRenderer(&templatesOverrideOptions{
+			filesystem: fstest.MapFS{
+				"blocks.tmpl": &fstest.MapFile{Data: []byte(`{{ define "input.label" }}<label>{{ t .Label }}</label>{{ end }}`)},
+			},
+			patterns: []string{"*.tmpl"},
+		}, ThemeDefault)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		el := Text("username").SetAttributes(Id("username")).SetLabel("username")
+		result, err := r.RenderE("input.tmpl", el)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := `<div><label>USERNAME</label><div><input id="username" name="username" type="text"></div></div>`
+		if cleanHTML(result) != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, cleanHTML(result))
+		}
+	})
+}
+
+func TestBufferPool(t *testing.T) {
+	t.Run("returns reset buffers", func(t *testing.T) {
+		buf := getBuffer()
+		buf.WriteString("stale content")
+		putBuffer(buf)
+
+		buf = getBuffer()
+		defer putBuffer(buf)
+
+		if buf.Len() != 0 {
+			t.Errorf("expected an empty buffer, got %q", buf.String())
+		}
+	})
+
+	t.Run("renders consistently with pooled buffers", func(t *testing.T) {
+		f := Form().AddChildren(Text("username"), Group(Email("email")))
 
-		// Should contain error message
-		if !strings.Contains(resultStr, "can't evaluate field NonExistent") {
-			t.Errorf("expected error message about NonExistent field, got %s", resultStr)
+		first, err := f.RenderE()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for range 5 {
+			result, err := f.RenderE()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != first {
+				t.Errorf("expected identical output, got %s", result)
+			}
+		}
+	})
+}
+
+func TestSetStrictOverridingTemplates(t *testing.T) {
+	t.Run("rejects patterns without matches", func(t *testing.T) {
+		options := &templatesOverrideOptions{
+			filesystem: fstest.MapFS{
+				"input.tmpl": &fstest.MapFile{Data: []byte("<input>")},
+			},
+			patterns: []string{"missing/*.tmpl"},
+		}
+
+		if err := options.validate(); !errors.Is(err, ErrTemplateNotFound) {
+			t.Errorf("expected ErrTemplateNotFound, got %v", err)
+		}
+	})
+
+	t.Run("rejects malformed templates", func(t *testing.T) {
+		options := &templatesOverrideOptions{
+			filesystem: fstest.MapFS{
+				"input.tmpl": &fstest.MapFile{Data: []byte("{{ if }")},
+			},
+			patterns: []string{"*.tmpl"},
+		}
+
+		if err := options.validate(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := newTemplateRenderer(options, ThemeDefault); err == nil {
+			t.Error("expected a parse error")
+		}
+	})
+
+	t.Run("rejects unknown functions", func(t *testing.T) {
+		options := &templatesOverrideOptions{
+			filesystem: fstest.MapFS{
+				"input.tmpl": &fstest.MapFile{Data: []byte("{{ unknown_func . }}")},
+			},
+			patterns: []string{"*.tmpl"},
+		}
+
+		if _, err := newTemplateRenderer(options, ThemeDefault); err == nil {
+			t.Error("expected a parse error")
+		}
+	})
+
+	t.Run("fails once templates are loaded", func(t *testing.T) {
+		getTemplateRenderer()
+
+		filesystem := fstest.MapFS{
+			"input.tmpl": &fstest.MapFile{Data: []byte("<input>")},
+		}
+
+		err := SetStrictOverridingTemplates(filesystem, "*.tmpl")
+		if !errors.Is(err, ErrTemplatesAlreadyLoaded) {
+			t.Errorf("expected ErrTemplatesAlreadyLoaded, got %v", err)
 		}
 	})
 }
@@ -48,7 +409,10 @@ func TestElementRenderer(t *testing.T) {
 		renderer := &mockRenderer{html: "<input type=\"text\" name=\"test\">"}
 		elementRendererFunc := componentRenderer()
 
-		result := elementRendererFunc(renderer)
+		result, err := elementRendererFunc(renderer)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 		expected := template.HTML("<input type=\"text\" name=\"test\">")
 
 		if result != expected {
@@ -60,7 +424,10 @@ func TestElementRenderer(t *testing.T) {
 		renderer := &mockRenderer{html: ""}
 		elementRendererFunc := componentRenderer()
 
-		result := elementRendererFunc(renderer)
+		result, err := elementRendererFunc(renderer)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 		expected := template.HTML("")
 
 		if result != expected {
@@ -77,8 +444,12 @@ func TestFormRenderer(t *testing.T) {
 		// Both should be the same type of function
 		renderer := &mockRenderer{html: "<div>test</div>"}
 
-		formResult := formRendererFunc(renderer)
-		elementResult := elementRendererFunc(renderer)
+		formResult, formErr := formRendererFunc(renderer)
+		elementResult, elementErr := elementRendererFunc(renderer)
+
+		if formErr != nil || elementErr != nil {
+			t.Errorf("unexpected errors: %v, %v", formErr, elementErr)
+		}
 
 		if formResult != elementResult {
 			t.Errorf("FormRenderer should return same result as elementRenderer")
@@ -206,35 +577,20 @@ func TestAttributesRenderer(t *testing.T) {
 		}
 	})
 
-	t.Run("handles unsupported data types", func(t *testing.T) {
-		attrs := map[string]any{
-			"name":   "username",
-			"number": 42,
-			"slice":  []string{"a", "b"},
-			"struct": struct{ X int }{X: 1},
-		}
-		result, err := attributesRendererFunc(attrs)
-
-		if err != nil {
-			t.Errorf("unexpected error: %v", err)
-		}
-
-		resultStr := string(result)
-
-		// Should contain error messages for unsupported types
-		if !strings.Contains(resultStr, "attribute number has an unsupported data type int") {
-			t.Error("expected error message for int type")
-		}
-		if !strings.Contains(resultStr, "attribute slice has an unsupported data type []string") {
-			t.Error("expected error message for slice type")
-		}
-		if !strings.Contains(resultStr, "attribute struct has an unsupported data type struct") {
-			t.Error("expected error message for struct type")
-		}
+	t.Run("returns an error for unsupported data types", func(t *testing.T) {
+		for _, value := range []any{42, []string{"a", "b"}, struct{ X int }{X: 1}} {
+			attrs := map[string]any{
+				"name":   "username",
+				"broken": value,
+			}
+			result, err := attributesRendererFunc(attrs)
 
-		// Should still include valid attributes
-		if !strings.Contains(resultStr, `name="username"`) {
-			t.Error("expected valid attributes to still be included")
+			if !errors.Is(err, ErrUnsupportedAttributeType) {
+				t.Errorf("expected ErrUnsupportedAttributeType for %T, got %v", value, err)
+			}
+			if result != template.HTMLAttr("") {
+				t.Errorf("expected no attributes to be rendered for %T, got %s", value, result)
+			}
 		}
 	})
 
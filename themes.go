@@ -0,0 +1,102 @@
+package goform
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type Theme string
+
+const (
+	ThemeDefault    Theme = ""
+	ThemeBootstrap5 Theme = "bootstrap5"
+	ThemeTailwind   Theme = "tailwind"
+	ThemePico       Theme = "pico"
+	ThemeBulma      Theme = "bulma"
+)
+
+var ErrUnknownTheme = errors.New("unknown theme")
+
+var themes = []Theme{
+	ThemeBootstrap5,
+	ThemeTailwind,
+	ThemePico,
+	ThemeBulma,
+}
+
+func (t Theme) validate() error {
+	if t == ThemeDefault || slices.Contains(themes, t) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownTheme, t)
+}
+
+func (t Theme) pattern() string {
+	return fmt.Sprintf("templates/themes/%s/*.tmpl", t)
+}
+
+var defaultTheme = ThemeDefault
+
+var themeRenderers = struct {
+	sync.Mutex
+	renderers map[Theme]*templateRenderer
+}{
+	renderers: make(map[Theme]*templateRenderer),
+}
+
+// SetDefaultTheme selects the theme used by forms that do not specify one
+func SetDefaultTheme(theme Theme) error {
+	if err := theme.validate(); err != nil {
+		return err
+	}
+
+	if templatesLoaded.Load() {
+		return ErrTemplatesAlreadyLoaded
+	}
+
+	defaultTheme = theme
+
+	return nil
+}
+
+func getThemeRenderer(theme Theme) *templateRenderer {
+	themeRenderers.Lock()
+	defer themeRenderers.Unlock()
+
+	templatesLoaded.Store(true)
+
+	if r, ok := themeRenderers.renderers[theme]; ok {
+		return r
+	}
+
+	if err := theme.validate(); err != nil {
+		panic(err)
+	}
+
+	r, err := newTemplateRenderer(templatesOptions, theme)
+	if err != nil {
+		panic(err)
+	}
+
+	themeRenderers.renderers[theme] = r
+
+	return r
+}
+
+func getTemplateRenderer() *templateRenderer {
+	return getThemeRenderer(defaultTheme)
+}
+
+type themeable interface {
+	setRenderer(renderer TemplateRenderer)
+}
+
+func applyRenderer(renderer TemplateRenderer, children ...Renderer) {
+	for _, c := range children {
+		if t, ok := c.(themeable); ok {
+			t.setRenderer(renderer)
+		}
+	}
+}
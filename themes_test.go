@@ -0,0 +1,144 @@
+package goform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTheme_Validate(t *testing.T) {
+	for _, theme := range []Theme{ThemeDefault, ThemeBootstrap5, ThemeTailwind, ThemePico, ThemeBulma} {
+		if err := theme.validate(); err != nil {
+			t.Errorf("expected theme %q to be valid, got %v", theme, err)
+		}
+	}
+
+	if err := Theme("unknown").validate(); !errors.Is(err, ErrUnknownTheme) {
+		t.Errorf("expected ErrUnknownTheme, got %v", err)
+	}
+}
+
+func TestSetDefaultTheme(t *testing.T) {
+	t.Run("rejects unknown themes", func(t *testing.T) {
+		if err := SetDefaultTheme(Theme("unknown")); !errors.Is(err, ErrUnknownTheme) {
+			t.Errorf("expected ErrUnknownTheme, got %v", err)
+		}
+	})
+
+	t.Run("fails once templates are loaded", func(t *testing.T) {
+		getTemplateRenderer()
+
+		if err := SetDefaultTheme(ThemeBootstrap5); !errors.Is(err, ErrTemplatesAlreadyLoaded) {
+			t.Errorf("expected ErrTemplatesAlreadyLoaded, got %v", err)
+		}
+	})
+}
+
+func TestWithTheme(t *testing.T) {
+	t.Run("renders every theme", func(t *testing.T) {
+		for _, theme := range themes {
+			f := Form(WithTheme(theme)).AddChildren(
+				FieldSet("Account",
+					Text("username").SetLabel("Username").SetHint("Pick one").SetError("Required"),
+					Checkbox("terms").SetLabel("Terms"),
+					Radio("plan").SetOptions(Option("Free", "free")),
+					Select("country").SetOptions(Option("France", "fr")),
+					Textarea("bio"),
+				),
+				Group(Submit("save")),
+			)
+
+			if _, err := f.RenderE(); err != nil {
+				t.Errorf("theme %s: unexpected error: %v", theme, err)
+			}
+		}
+	})
+
+	t.Run("propagates the renderer to nested children", func(t *testing.T) {
+		el := Text("username")
+		g := Group(el)
+		fs := FieldSet("Account", g)
+		f := Form(WithTheme(ThemeBootstrap5)).AddChildren(fs)
+
+		if fs.renderer != f.renderer || g.renderer != f.renderer || el.renderer != f.renderer {
+			t.Error("expected children to use the form renderer")
+		}
+	})
+
+	t.Run("bootstrap 5", func(t *testing.T) {
+		el := Text("username").
+			SetAttributes(Id("username"), Attr("required", true)).
+			SetLabel("Username").
+			SetHint("Your public name").
+			SetError("Username is required")
+		Form(WithTheme(ThemeBootstrap5)).AddChildren(el)
+
+		expected := `<div class="mb-3"><label for="username" class="form-label">Username <span class="text-danger">*</span></label><input aria-describedby="username-hint username-error" aria-errormessage="username-error" class="form-control is-invalid" id="username" name="username" required type="text"><div id="username-error" class="invalid-feedback d-block">Username is required</div><div id="username-hint" class="form-text">Your public name</div></div>`
+
+		if result := cleanHTML(el.Render()); result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, result)
+		}
+	})
+
+	t.Run("tailwind", func(t *testing.T) {
+		el := Email("email").SetAttributes(Id("email")).SetError("Invalid email")
+		Form(WithTheme(ThemeTailwind)).AddChildren(el)
+
+		result := cleanHTML(el.Render())
+		if !strings.Contains(result, `class="mt-1 block w-full rounded-md border-red-300`) {
+			t.Errorf("expected invalid control classes, got %s", result)
+		}
+		if !strings.Contains(result, `<p id="email-error" class="mt-2 text-sm text-red-600">Invalid email</p>`) {
+			t.Errorf("expected error message, got %s", result)
+		}
+	})
+
+	t.Run("pico", func(t *testing.T) {
+		el := Text("name").SetAttributes(Id("name")).SetLabel("Name").SetError("Required")
+		Form(WithTheme(ThemePico)).AddChildren(el)
+
+		expected := `<label for="name">Name</label><input aria-describedby="name-error" aria-errormessage="name-error" aria-invalid="true" id="name" name="name" type="text"><small id="name-error">Required</small>`
+
+		if result := cleanHTML(el.Render()); result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, result)
+		}
+	})
+
+	t.Run("bulma", func(t *testing.T) {
+		el := Text("name").SetAttributes(Id("name")).SetLabel("Name").SetError("Required")
+		Form(WithTheme(ThemeBulma)).AddChildren(el)
+
+		expected := `<div class="field"><label for="name" class="label">Name</label><div class="control"><input aria-describedby="name-error" aria-errormessage="name-error" class="input is-danger" id="name" name="name" type="text"></div><p id="name-error" class="help is-danger">Required</p></div>`
+
+		if result := cleanHTML(el.Render()); result != expected {
+			t.Errorf("expected exact HTML match:\nExpected: %s\nActual:   %s", expected, result)
+		}
+	})
+
+	t.Run("keeps user classes", func(t *testing.T) {
+		el := Text("name").SetAttributes(Id("name"), Attr("class", "wide"))
+		Form(WithTheme(ThemeBootstrap5)).AddChildren(el)
+
+		result := cleanHTML(el.Render())
+		if !strings.Contains(result, `class="wide form-control"`) {
+			t.Errorf("expected merged classes, got %s", result)
+		}
+		if el.Attributes().String("class") != "wide" {
+			t.Errorf("expected element attributes to be left untouched, got %s", el.Attributes().String("class"))
+		}
+	})
+}
+
+func TestClassModifier(t *testing.T) {
+	fn := classModifier()
+
+	result := fn(Attrs{"class": "a b"}, "b c", "", "d")
+	if result.String("class") != "a b c d" {
+		t.Errorf("expected a b c d, got %s", result.String("class"))
+	}
+
+	result = fn(nil, "a")
+	if result.String("class") != "a" {
+		t.Errorf("expected a, got %s", result.String("class"))
+	}
+}